@@ -0,0 +1,288 @@
+package aubio
+
+import (
+	"log"
+	"math"
+)
+
+// barLowBandHz is the upper edge of the band BeatFeatureExtractor
+// measures the low energy in, where kick drums and bass notes sit.
+const barLowBandHz = 150
+
+// BeatFeatures describes the audio between a beat and the next one.
+// Downbeats tend to have more low energy, more harmonic change and
+// stronger onsets than the other beats of a bar.
+type BeatFeatures struct {
+	// Time of the beat in seconds.
+	Time float64
+	// LowEnergy is the mean energy below 150Hz.
+	LowEnergy float64
+	// ChromaChange is 1 minus the cosine similarity between the
+	// chroma of this beat and of the previous one.
+	ChromaChange float64
+	// OnsetStrength is the strongest onset detection value.
+	OnsetStrength float64
+}
+
+// BarPosition is the position of a beat within its bar.
+type BarPosition struct {
+	// Beat is the index of the beat within the bar, 0 being the downbeat.
+	Beat int
+	// BeatsPerBar is the estimated numerator of the time signature.
+	BeatsPerBar int
+	// Confidence is between 0 and 1.
+	Confidence float64
+}
+
+// Downbeat returns if the beat is the first of its bar.
+func (p BarPosition) Downbeat() bool {
+	return p.BeatsPerBar > 0 && p.Beat == 0
+}
+
+// BarTracker estimates the time signature and the downbeats from the
+// features of successive beats. Every hypothesis of meter and phase is
+// scored by how much more salient the beats it places on the "one" are
+// than the other beats.
+type BarTracker struct {
+	features []BeatFeatures
+	position BarPosition
+
+	// Meters are the candidate numbers of beats per bar.
+	// Defaults to 3 and 4.
+	Meters []int
+	// Memory is the number of beats the estimate is based on, at
+	// least 1. Defaults to 32.
+	Memory int
+	// Weights of each feature in the salience of a beat.
+	// They default to 1, 1 and 0.5.
+	LowEnergyWeight    float64
+	ChromaChangeWeight float64
+	OnsetWeight        float64
+}
+
+// NewBarTracker constructs a new BarTracker.
+func NewBarTracker() *BarTracker {
+	return &BarTracker{
+		Meters:             []int{3, 4},
+		Memory:             32,
+		LowEnergyWeight:    1,
+		ChromaChangeWeight: 1,
+		OnsetWeight:        0.5,
+	}
+}
+
+// Beat adds the features of a new beat and returns its position
+// within the bar.
+func (bt *BarTracker) Beat(f BeatFeatures) BarPosition {
+	bt.features = append(bt.features, f)
+	if memory := maxInt(bt.Memory, 1); len(bt.features) > memory {
+		bt.features = bt.features[len(bt.features)-memory:]
+	}
+	bt.position = bt.estimate()
+	return bt.position
+}
+
+// Position returns the position of the latest beat.
+func (bt *BarTracker) Position() BarPosition {
+	return bt.position
+}
+
+// Reset forgets all the beats seen so far.
+func (bt *BarTracker) Reset() {
+	bt.features = nil
+	bt.position = BarPosition{}
+}
+
+func (bt *BarTracker) estimate() BarPosition {
+	salience := bt.salience()
+	n := len(salience)
+	best, second := math.Inf(-1), math.Inf(-1)
+	var pos BarPosition
+	for _, meter := range bt.Meters {
+		// wait for two full bars of the meter
+		if meter < 2 || n < 2*meter {
+			continue
+		}
+		for phase := 0; phase < meter; phase++ {
+			var on, off float64
+			var nOn, nOff int
+			for i, s := range salience {
+				if (i-phase)%meter == 0 {
+					on += s
+					nOn++
+				} else {
+					off += s
+					nOff++
+				}
+			}
+			score := on/float64(nOn) - off/float64(nOff)
+			if score > best {
+				second = best
+				best = score
+				// the latest beat is salience[n-1]
+				pos = BarPosition{Beat: ((n-1-phase)%meter + meter) % meter, BeatsPerBar: meter}
+			} else if score > second {
+				second = score
+			}
+		}
+	}
+	if pos.BeatsPerBar == 0 || best <= 0 {
+		return BarPosition{}
+	}
+	pos.Confidence = 1
+	if !math.IsInf(second, -1) {
+		pos.Confidence = math.Min(1, math.Max(0, (best-second)/best))
+	}
+	return pos
+}
+
+// salience combines the features of each beat, after standardizing
+// them over the beats remembered.
+func (bt *BarTracker) salience() []float64 {
+	s := make([]float64, len(bt.features))
+	for _, f := range []struct {
+		weight float64
+		get    func(BeatFeatures) float64
+	}{
+		{bt.LowEnergyWeight, func(f BeatFeatures) float64 { return f.LowEnergy }},
+		{bt.ChromaChangeWeight, func(f BeatFeatures) float64 { return f.ChromaChange }},
+		{bt.OnsetWeight, func(f BeatFeatures) float64 { return f.OnsetStrength }},
+	} {
+		if f.weight == 0 {
+			continue
+		}
+		var mean, variance float64
+		for _, bf := range bt.features {
+			mean += f.get(bf)
+		}
+		mean /= float64(len(bt.features))
+		for _, bf := range bt.features {
+			variance += (f.get(bf) - mean) * (f.get(bf) - mean)
+		}
+		if variance == 0 {
+			continue
+		}
+		std := math.Sqrt(variance / float64(len(bt.features)))
+		for i, bf := range bt.features {
+			s[i] += f.weight * (f.get(bf) - mean) / std
+		}
+	}
+	return s
+}
+
+// BeatFeatureExtractor computes the BeatFeatures used by BarTracker
+// from audio.
+type BeatFeatureExtractor struct {
+	pv       *PhaseVoc
+	chroma   *Chroma
+	onset    *SpecDesc
+	lowBins  int
+	energy   float64
+	strength float64
+	frames   int
+	sum      []float64
+	previous []float64
+}
+
+// NewBeatFeatureExtractor constructs a new BeatFeatureExtractor.
+// It is the Callers responsibility to call Free on the returned
+// BeatFeatureExtractor object or leak memory.
+//     fe, err := NewBeatFeatureExtractor(bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer fe.Free()
+func NewBeatFeatureExtractor(bufSize, hopSize, samplerate uint) (*BeatFeatureExtractor, error) {
+	pv, err := NewPhaseVoc(bufSize, hopSize)
+	if err != nil {
+		return nil, err
+	}
+	chroma, err := NewChroma(bufSize, samplerate)
+	if err != nil {
+		pv.Free()
+		return nil, err
+	}
+	onset, err := NewSpecDesc(SpecDescSpecFlux, bufSize)
+	if err != nil {
+		pv.Free()
+		chroma.Free()
+		return nil, err
+	}
+	return &BeatFeatureExtractor{
+		pv:      pv,
+		chroma:  chroma,
+		onset:   onset,
+		lowBins: int(math.Ceil(barLowBandHz * float64(bufSize) / float64(samplerate))),
+		sum:     make([]float64, ChromaBins),
+	}, nil
+}
+
+// Do analyzes a hopSize input Buffer.
+func (fe *BeatFeatureExtractor) Do(input *SimpleBuffer) {
+	if fe.pv == nil {
+		log.Println("Called Do on empty BeatFeatureExtractor. Maybe you called Free previously?")
+		return
+	}
+	fe.pv.Do(input)
+	grain := fe.pv.Grain()
+	norm := grain.Norm()
+	var energy float64
+	for k := 1; k <= fe.lowBins && k < len(norm); k++ {
+		energy += norm[k] * norm[k]
+	}
+	fe.energy += energy
+	fe.onset.Do(grain)
+	fe.strength = math.Max(fe.strength, fe.onset.Value())
+	fe.chroma.Do(grain)
+	for i, v := range fe.chroma.Buffer().Slice() {
+		fe.sum[i] += v
+	}
+	fe.frames++
+}
+
+// Beat closes the features of the beat at t seconds, computed from
+// the hops analyzed since the previous call to Beat.
+func (fe *BeatFeatureExtractor) Beat(t float64) BeatFeatures {
+	f := BeatFeatures{Time: t, OnsetStrength: fe.strength}
+	if fe.frames > 0 {
+		f.LowEnergy = fe.energy / float64(fe.frames)
+	}
+	if fe.previous != nil {
+		f.ChromaChange = 1 - cosineSimilarity(fe.previous, fe.sum)
+	}
+	fe.previous = append(fe.previous[:0], fe.sum...)
+	for i := range fe.sum {
+		fe.sum[i] = 0
+	}
+	fe.energy, fe.strength, fe.frames = 0, 0, 0
+	return f
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (fe *BeatFeatureExtractor) Free() {
+	if fe.pv != nil {
+		fe.pv.Free()
+		fe.pv = nil
+	}
+	if fe.chroma != nil {
+		fe.chroma.Free()
+		fe.chroma = nil
+	}
+	if fe.onset != nil {
+		fe.onset.Free()
+		fe.onset = nil
+	}
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,146 @@
+package aubio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// barFeatures synthesizes the features of n beats in bars of meter
+// beats, the first beat of the sequence being beat first of its bar.
+func barFeatures(meter, first, n int, rng *rand.Rand) []BeatFeatures {
+	features := make([]BeatFeatures, n)
+	for i := range features {
+		f := BeatFeatures{
+			Time:          float64(i) * 0.5,
+			LowEnergy:     1 + 0.3*rng.Float64(),
+			ChromaChange:  0.1 + 0.1*rng.Float64(),
+			OnsetStrength: 1 + 0.5*rng.Float64(),
+		}
+		switch (first + i) % meter {
+		case 0:
+			f.LowEnergy += 1
+			f.ChromaChange += 0.3
+		case 2:
+			if meter == 4 {
+				f.LowEnergy += 0.4
+			}
+		}
+		features[i] = f
+	}
+	return features
+}
+
+func TestBarTracker(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, tc := range []struct{ meter, first int }{{4, 0}, {4, 1}, {3, 0}, {3, 2}} {
+		bt := NewBarTracker()
+		var pos BarPosition
+		for i, f := range barFeatures(tc.meter, tc.first, 24, rng) {
+			pos = bt.Beat(f)
+			if i < 2*tc.meter-1 {
+				continue
+			}
+			want := (tc.first + i) % tc.meter
+			if i >= 12 && (pos.BeatsPerBar != tc.meter || pos.Beat != want) {
+				t.Errorf("%d/4 from beat %d: beat %d got %+v, want beat %d", tc.meter, tc.first, i, pos, want)
+			}
+		}
+		if pos.Confidence <= 0 || pos.Confidence > 1 {
+			t.Errorf("%d/4: confidence %v", tc.meter, pos.Confidence)
+		}
+	}
+}
+
+func TestBarTrackerNeedsTwoBars(t *testing.T) {
+	bt := NewBarTracker()
+	for i, f := range barFeatures(4, 0, 5, rand.New(rand.NewSource(2))) {
+		if pos := bt.Beat(f); pos.BeatsPerBar != 0 {
+			t.Errorf("beat %d: got %+v before two bars", i, pos)
+		}
+	}
+}
+
+func TestBarTrackerFlatFeatures(t *testing.T) {
+	bt := NewBarTracker()
+	for i := 0; i < 16; i++ {
+		if pos := bt.Beat(BeatFeatures{Time: float64(i), LowEnergy: 1}); pos.Downbeat() {
+			t.Fatalf("downbeat found in flat features: %+v", pos)
+		}
+	}
+}
+
+func TestBarTrackerMemory(t *testing.T) {
+	bt := NewBarTracker()
+	bt.Memory = -1
+	// must not crash, and remembers a single beat
+	for _, f := range barFeatures(4, 0, 8, rand.New(rand.NewSource(3))) {
+		if pos := bt.Beat(f); pos.BeatsPerBar != 0 {
+			t.Errorf("got %+v from a single beat", pos)
+		}
+	}
+	if len(bt.features) != 1 {
+		t.Errorf("remembered %d beats, want 1", len(bt.features))
+	}
+}
+
+func TestBeatFeatureExtractor(t *testing.T) {
+	fe, err := NewBeatFeatureExtractor(testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fe.Free()
+	// four beats of half a second: a C major chord over a 55Hz bass,
+	// the chord alone twice, then an F# major chord
+	const beat = testSamplerate / 2
+	signal := make([]float64, 4*beat)
+	bass := tone(55, testSamplerate, beat)
+	cMajor := make([]float64, 3*beat)
+	for _, midi := range []float64{60, 64, 67} {
+		for i, v := range tone(MidiToFreq(midi), testSamplerate, 3*beat) {
+			cMajor[i] += v / 3
+		}
+	}
+	for _, midi := range []float64{66, 70, 73} {
+		for i, v := range tone(MidiToFreq(midi), testSamplerate, beat) {
+			signal[3*beat+i] += v / 3
+		}
+	}
+	for i := range cMajor {
+		signal[i] += cMajor[i]
+	}
+	for i := range bass {
+		signal[i] += bass[i]
+	}
+
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	var features []BeatFeatures
+	for i := 0; i < len(signal); i += beat {
+		for j := i; j+testHopSize <= i+beat; j += testHopSize {
+			buf.SetData(signal[j : j+testHopSize])
+			fe.Do(buf)
+		}
+		features = append(features, fe.Beat(float64(i)/testSamplerate))
+	}
+	for i, f := range features {
+		if want := float64(i) / 2; math.Abs(f.Time-want) > 1e-9 {
+			t.Errorf("beat %d at %vs, want %vs", i, f.Time, want)
+		}
+	}
+	if f := features[0]; f.ChromaChange != 0 || f.OnsetStrength <= 0 {
+		t.Errorf("first beat %+v, want no chroma change and an onset", f)
+	}
+	if features[0].LowEnergy < 3*features[1].LowEnergy {
+		t.Errorf("low energy %v with the bass, %v without, want it much higher with it",
+			features[0].LowEnergy, features[1].LowEnergy)
+	}
+	if features[3].ChromaChange < 2*features[2].ChromaChange {
+		t.Errorf("chroma change %v on the new chord, %v on the repeated one, want it much higher on the new one",
+			features[3].ChromaChange, features[2].ChromaChange)
+	}
+	if features[3].OnsetStrength <= features[2].OnsetStrength {
+		t.Errorf("onset strength %v on the new chord, %v on the repeated one, want it higher on the new one",
+			features[3].OnsetStrength, features[2].OnsetStrength)
+	}
+}
@@ -0,0 +1,158 @@
+package aubio
+
+import (
+	"math"
+)
+
+// BeatClock is a phase locked clock driven by the beats Tempo detects.
+// It predicts upcoming beats so that effects can be triggered ahead of
+// them rather than one hop after Tempo reports them. It keeps ticking
+// at the last known tempo through short dropouts and adjusts its
+// period and phase smoothly when the detected tempo changes.
+type BeatClock struct {
+	hop      float64
+	now      float64
+	period   float64
+	anchor   float64
+	lastSeen float64
+	lastTick float64
+	synced   bool
+	ticked   bool
+
+	// PeriodSmoothing is the fraction of the difference between the
+	// detected and the current period applied on each beat.
+	// Defaults to 0.3.
+	PeriodSmoothing float64
+	// PhaseSmoothing is the fraction of the phase error corrected on
+	// each beat. Defaults to 0.5.
+	PhaseSmoothing float64
+	// Dropout is how long in seconds the clock keeps running without
+	// detected beats. Defaults to 4s.
+	Dropout float64
+	// Lookahead is how early in seconds Ticked fires before each
+	// predicted beat.
+	Lookahead float64
+}
+
+// NewBeatClock constructs a new BeatClock advancing by hopSize samples
+// on each call to Do.
+func NewBeatClock(hopSize, samplerate uint) *BeatClock {
+	return &BeatClock{
+		hop:             float64(hopSize) / float64(samplerate),
+		lastTick:        math.Inf(-1),
+		PeriodSmoothing: 0.3,
+		PhaseSmoothing:  0.5,
+		Dropout:         4,
+	}
+}
+
+// Do advances the clock by one hop and synchronizes it with t, on
+// which Do must have just been called.
+func (c *BeatClock) Do(t *Tempo) {
+	c.Advance(c.hop)
+	if t.IsBeat() {
+		c.Beat(t.GetLastS(), t.GetPeriodS())
+	}
+}
+
+// Advance moves the clock forward by dt seconds.
+func (c *BeatClock) Advance(dt float64) {
+	c.now += dt
+	c.ticked = false
+	if !c.Running() {
+		return
+	}
+	// tick once per beat, and never for beats before the previous hop
+	from := math.Max(c.lastTick+c.period/2, c.now+c.Lookahead-dt)
+	if next := c.beatFrom(from); next <= c.now+c.Lookahead {
+		c.ticked = true
+		c.lastTick = next
+	}
+}
+
+// Beat synchronizes the clock with a beat detected at t seconds,
+// while the beat period was period seconds.
+func (c *BeatClock) Beat(t, period float64) {
+	if period <= 0 {
+		return
+	}
+	running := c.Running()
+	c.lastSeen = t
+	if !running {
+		c.period = period
+		c.anchor = t
+		c.synced = true
+		return
+	}
+	c.period += c.PeriodSmoothing * (period - c.period)
+	predicted := c.anchor + math.Round((t-c.anchor)/c.period)*c.period
+	c.anchor = predicted + c.PhaseSmoothing*(t-predicted)
+}
+
+// Running returns if the clock is synchronized and has seen a beat
+// within the last Dropout seconds.
+func (c *BeatClock) Running() bool {
+	return c.synced && c.now-c.lastSeen <= c.Dropout
+}
+
+// Ticked returns if the virtual metronome ticked during the latest
+// call to Do or Advance, Lookahead seconds before a predicted beat.
+func (c *BeatClock) Ticked() bool {
+	return c.ticked
+}
+
+// Now returns the time of the clock in seconds.
+func (c *BeatClock) Now() float64 {
+	return c.now
+}
+
+// Period returns the beat period in seconds, or 0 when not running.
+func (c *BeatClock) Period() float64 {
+	if !c.Running() {
+		return 0
+	}
+	return c.period
+}
+
+// BPM returns the tempo of the clock, or 0 when not running.
+func (c *BeatClock) BPM() float64 {
+	if !c.Running() {
+		return 0
+	}
+	return 60 / c.period
+}
+
+// Phase returns the position of the clock between two beats, from 0
+// on a beat to 1 on the next one. It is 0 when not running.
+func (c *BeatClock) Phase() float64 {
+	if !c.Running() {
+		return 0
+	}
+	phase := math.Mod((c.now-c.anchor)/c.period, 1)
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+// NextBeats returns the predicted times in seconds of the next n
+// beats strictly after Now. It returns nil when not running.
+func (c *BeatClock) NextBeats(n int) []float64 {
+	if !c.Running() || n <= 0 {
+		return nil
+	}
+	next := c.beatFrom(c.now)
+	if next <= c.now {
+		next += c.period
+	}
+	beats := make([]float64, n)
+	for i := range beats {
+		beats[i] = next + float64(i)*c.period
+	}
+	return beats
+}
+
+// beatFrom returns the first predicted beat at or after t.
+func (c *BeatClock) beatFrom(t float64) float64 {
+	return c.anchor + math.Ceil((t-c.anchor)/c.period)*c.period
+}
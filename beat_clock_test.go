@@ -0,0 +1,149 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+const clockHop = float64(testHopSize) / testSamplerate
+
+// runClock feeds c the beats of a click track, reported with the
+// latency of one hop, until the time end.
+func runClock(c *BeatClock, beats []float64, period, end float64, tick func()) {
+	for c.Now() < end {
+		c.Advance(clockHop)
+		for len(beats) > 0 && beats[0] <= c.Now() {
+			c.Beat(beats[0], period)
+			beats = beats[1:]
+		}
+		if tick != nil {
+			tick()
+		}
+	}
+}
+
+func clickTimes(bpm, start, end float64) []float64 {
+	var beats []float64
+	for t := start; t < end; t += 60 / bpm {
+		beats = append(beats, t)
+	}
+	return beats
+}
+
+func TestBeatClockPredictsBeats(t *testing.T) {
+	c := NewBeatClock(testHopSize, testSamplerate)
+	runClock(c, clickTimes(120, 0.25, 10), 0.5, 10.1, nil)
+	if math.Abs(c.BPM()-120) > 1e-6 {
+		t.Errorf("BPM = %v, want 120", c.BPM())
+	}
+	next := c.NextBeats(4)
+	for i, want := range []float64{10.25, 10.75, 11.25, 11.75} {
+		if math.Abs(next[i]-want) > 1e-6 {
+			t.Errorf("next beats %v, want %v at %d", next, want, i)
+		}
+	}
+	if want := math.Mod(c.Now()-0.25, 0.5) / 0.5; math.Abs(c.Phase()-want) > 1e-6 {
+		t.Errorf("phase = %v, want %v", c.Phase(), want)
+	}
+}
+
+func TestBeatClockMetronome(t *testing.T) {
+	c := NewBeatClock(testHopSize, testSamplerate)
+	c.Lookahead = 0.1
+	var ticks []float64
+	runClock(c, clickTimes(100, 0, 12), 0.6, 12, func() {
+		if c.Ticked() {
+			ticks = append(ticks, c.Now())
+		}
+	})
+	if len(ticks) < 18 {
+		t.Fatalf("got %d ticks, want about 20", len(ticks))
+	}
+	for _, tick := range ticks[1:] {
+		// each tick falls in the hop Lookahead before a beat
+		before := math.Mod(tick+c.Lookahead, 0.6)
+		if before > clockHop+1e-9 {
+			t.Errorf("tick at %v is %vs past the lookahead", tick, before)
+		}
+	}
+}
+
+func TestBeatClockDropout(t *testing.T) {
+	c := NewBeatClock(testHopSize, testSamplerate)
+	runClock(c, clickTimes(120, 0, 5), 0.5, 7, nil)
+	if !c.Running() {
+		t.Fatal("clock stopped during a 2s dropout")
+	}
+	if next := c.NextBeats(1)[0]; math.Abs(math.Remainder(next, 0.5)) > 1e-6 {
+		t.Errorf("next beat %v is off the grid during the dropout", next)
+	}
+	runClock(c, nil, 0.5, 10, nil)
+	if c.Running() || c.BPM() != 0 || c.NextBeats(1) != nil {
+		t.Error("clock still running after a 5s dropout")
+	}
+}
+
+func TestBeatClockTempoChange(t *testing.T) {
+	c := NewBeatClock(testHopSize, testSamplerate)
+	beats := append(clickTimes(120, 0, 10), clickTimes(128, 10, 20)...)
+	var last float64
+	for c.Now() < 20 {
+		c.Advance(clockHop)
+		for len(beats) > 0 && beats[0] <= c.Now() {
+			period := 0.5
+			if beats[0] >= 10 {
+				period = 60. / 128
+			}
+			c.Beat(beats[0], period)
+			beats = beats[1:]
+		}
+		// the tempo moves monotonically from 120 to 128 BPM
+		if bpm := c.BPM(); bpm < last-1e-9 || bpm > 128+1e-9 {
+			t.Fatalf("BPM jumped from %v to %v at %vs", last, bpm, c.Now())
+		}
+		last = c.BPM()
+	}
+	if math.Abs(last-128) > 0.01 {
+		t.Errorf("BPM = %v after the tempo change, want 128", last)
+	}
+	if next := c.NextBeats(1)[0]; math.Abs(math.Remainder(next-10, 60./128)) > 0.005 {
+		t.Errorf("next beat %v is off the 128 BPM grid", next)
+	}
+}
+
+func TestBeatClockTempo(t *testing.T) {
+	tempo, err := NewTempo(OnsetDefault, 2*testHopSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempo.Free()
+	c := NewBeatClock(testHopSize, testSamplerate)
+	signal := clickTrack(120, testSamplerate, 15*testSamplerate)
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		buf.SetData(signal[i : i+testHopSize])
+		tempo.Do(buf)
+		c.Do(tempo)
+	}
+	if math.Abs(c.BPM()-120) > 2 {
+		t.Errorf("BPM = %v, want 120", c.BPM())
+	}
+	next := c.NextBeats(2)
+	if len(next) != 2 || math.Abs(next[1]-next[0]-0.5) > 0.02 {
+		t.Errorf("next beats %v, want 0.5s apart", next)
+	}
+}
+
+// clickTrack synthesizes n samples of short clicks at bpm.
+func clickTrack(bpm float64, samplerate, n int) []float64 {
+	out := make([]float64, n)
+	period := 60 / bpm * float64(samplerate)
+	for beat := 0.; int(beat) < n; beat += period {
+		for i := 0; i < samplerate/100 && int(beat)+i < n; i++ {
+			out[int(beat)+i] = math.Sin(2*math.Pi*1000*float64(i)/float64(samplerate)) *
+				math.Exp(-float64(i)/float64(samplerate)*400)
+		}
+	}
+	return out
+}
@@ -174,6 +174,20 @@ func (cb *ComplexBuffer) Phase() []float64 {
 	return sl
 }
 
+// SetNorm copies data into the norm of this buffer.
+func (cb *ComplexBuffer) SetNorm(data []float64) {
+	for i := uint(0); i < cb.Size() && i < uint(len(data)); i++ {
+		C.cvec_norm_set_sample(cb.data, C.smpl_t(data[i]), C.uint_t(i))
+	}
+}
+
+// SetPhase copies data into the phase of this buffer.
+func (cb *ComplexBuffer) SetPhase(data []float64) {
+	for i := uint(0); i < cb.Size() && i < uint(len(data)); i++ {
+		C.cvec_phas_set_sample(cb.data, C.smpl_t(data[i]), C.uint_t(i))
+	}
+}
+
 // Buffer for Long sample data (64 bits)
 type LongSampleBuffer struct {
 	vec *C.lvec_t
@@ -7,16 +7,28 @@ import (
 
 func TestSimpleBufferSetFast(t *testing.T) {
 	b := NewSimpleBuffer(100)
-	data := make([]float64, 100)
+	data := make([]float32, 100)
 	data[0] = 1.56
 	b.SetDataFast(data)
 }
 
+func TestQuadraticPeakPos(t *testing.T) {
+	b := NewSimpleBufferData(5, []float64{0, 1, 3, 2, 0})
+	defer b.Free()
+	if got := b.QuadraticPeakPos(2); got <= 2 || got >= 2.5 {
+		t.Errorf("QuadraticPeakPos(2) = %v, want between 2 and 2.5", got)
+	}
+	if got := b.QuadraticPeakPos(5); got != 5 {
+		t.Errorf("QuadraticPeakPos(5) = %v past the end, want 5", got)
+	}
+}
+
 func BenchmarkSimpleBuffer(t *testing.B) {
 	lens := []int{50, 100, 500, 1000, 5000}
 	for _, l := range lens {
 		b := NewSimpleBuffer(uint(l))
 		data := make([]float64, l)
+		data32 := make([]float32, l)
 		t.Run(fmt.Sprintf("%v set data slow", l), func(t *testing.B) {
 			for i := 0; i < t.N; i++ {
 				b.SetData(data)
@@ -24,7 +36,7 @@ func BenchmarkSimpleBuffer(t *testing.B) {
 		})
 		t.Run(fmt.Sprintf("%v set data fast", l), func(t *testing.B) {
 			for i := 0; i < t.N; i++ {
-				b.SetDataFast(data)
+				b.SetDataFast(data32)
 			}
 		})
 	}
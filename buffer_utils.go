@@ -2,6 +2,7 @@ package aubio
 
 /*
 #cgo LDFLAGS: -laubio
+#define AUBIO_UNSTABLE 1
 #include <aubio/aubio.h>
 */
 import "C"
@@ -65,3 +66,19 @@ func (buf *SimpleBuffer) Pow(pow float64) {
 func (buf *SimpleBuffer) Clamp(absmax float64) {
 	C.fvec_clamp(buf.vec, C.smpl_t(absmax))
 }
+
+// Returns the position of the peak at index pos refined by quadratic
+// interpolation with its two neighbours. A pos past the end of the
+// buffer is returned unchanged
+func (buf *SimpleBuffer) QuadraticPeakPos(pos uint) float64 {
+	if pos >= buf.Size() {
+		return float64(pos)
+	}
+	return float64(C.fvec_quadratic_peak_pos(buf.vec, C.uint_t(pos)))
+}
+
+// Returns the magnitude of the peak at the fractional position pos
+// using quadratic interpolation
+func (buf *SimpleBuffer) QuadraticPeakMag(pos float64) float64 {
+	return float64(C.fvec_quadratic_peak_mag(buf.vec, C.smpl_t(pos)))
+}
@@ -0,0 +1,160 @@
+package aubio
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// cepstrumFloor is added to the magnitude spectrum before taking its
+// logarithm, so that silent bins do not produce infinities.
+const cepstrumFloor = 1e-10
+
+// Cepstrum computes the real cepstrum of a buffer: the inverse FFT of
+// the logarithm of its magnitude spectrum. Low quefrencies describe
+// the spectral envelope and high ones the periodicity of the signal.
+type Cepstrum struct {
+	fft      *FFT
+	spectrum *ComplexBuffer
+}
+
+// NewCepstrum constructs a new Cepstrum object for buffers of size
+// samples.
+// It is the Callers responsibility to call Free on the returned
+// Cepstrum object or leak memory.
+//     c, err := NewCepstrum(bufSize)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer c.Free()
+func NewCepstrum(size uint) (*Cepstrum, error) {
+	fft, err := NewFFT(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Cepstrum{fft: fft, spectrum: NewComplexBuffer(size)}, nil
+}
+
+// Buffer returns the cepstrum computed by the latest call to Do, with
+// one coefficient per quefrency in samples.
+func (c *Cepstrum) Buffer() *SimpleBuffer {
+	if c.fft == nil {
+		return nil
+	}
+	return c.fft.Buffer()
+}
+
+// Do computes the real cepstrum of a size input Buffer.
+func (c *Cepstrum) Do(in *SimpleBuffer) {
+	if c.spectrum == nil {
+		log.Println("Called Do on empty Cepstrum. Maybe you called Free previously?")
+		return
+	}
+	c.fft.Do(in)
+	c.DoSpectrum(c.fft.Grain())
+}
+
+// DoSpectrum computes the real cepstrum from a spectrum, such as the
+// output of PhaseVoc, whose phase is ignored.
+func (c *Cepstrum) DoSpectrum(in *ComplexBuffer) {
+	if c.spectrum == nil {
+		log.Println("Called DoSpectrum on empty Cepstrum. Maybe you called Free previously?")
+		return
+	}
+	norm := in.Norm()
+	for i := range norm {
+		norm[i] = math.Log(norm[i] + cepstrumFloor)
+	}
+	c.spectrum.SetNorm(norm)
+	c.spectrum.SetPhase(make([]float64, len(norm)))
+	c.fft.ReverseDo(c.spectrum)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (c *Cepstrum) Free() {
+	if c.fft != nil {
+		c.fft.Free()
+		c.fft = nil
+	}
+	if c.spectrum != nil {
+		c.spectrum.Free()
+		c.spectrum = nil
+	}
+}
+
+// LowLifter keeps the quefrencies of a real cepstrum below cutoff
+// samples, and their mirror images, and zeroes the others. The result
+// describes the spectral envelope.
+func LowLifter(cepstrum []float64, cutoff int) []float64 {
+	out := make([]float64, len(cepstrum))
+	for q := range cepstrum {
+		if q < cutoff || len(cepstrum)-q < cutoff {
+			out[q] = cepstrum[q]
+		}
+	}
+	return out
+}
+
+// HighLifter keeps the quefrencies of a real cepstrum from cutoff
+// samples up, and zeroes the lower ones and their mirror images.
+// The result describes the excitation.
+func HighLifter(cepstrum []float64, cutoff int) []float64 {
+	out := make([]float64, len(cepstrum))
+	for q := range cepstrum {
+		if q >= cutoff && len(cepstrum)-q >= cutoff {
+			out[q] = cepstrum[q]
+		}
+	}
+	return out
+}
+
+// SinLifter applies the sinusoidal lifter of HTK to cepstral
+// coefficients such as MFCCs, raising the higher ones so they all
+// have similar magnitudes. HTK uses l = 22. As in HTK, l = 0 leaves
+// the coefficients unchanged.
+func SinLifter(coeffs []float64, l float64) []float64 {
+	out := make([]float64, len(coeffs))
+	if l == 0 {
+		copy(out, coeffs)
+		return out
+	}
+	for n, c := range coeffs {
+		out[n] = c * (1 + l/2*math.Sin(math.Pi*float64(n)/l))
+	}
+	return out
+}
+
+// CepstralPitch returns the frequency of the strongest peak of a real
+// cepstrum with a quefrency between samplerate/maxHz and
+// samplerate/minHz, along with its height. It returns 0 when the range
+// holds no peak, and an error unless 0 < minHz < maxHz.
+func CepstralPitch(cepstrum []float64, samplerate uint, minHz, maxHz float64) (hz, strength float64, err error) {
+	if !(minHz > 0 && minHz < maxHz) {
+		return 0, 0, fmt.Errorf("invalid pitch range %v to %vHz", minHz, maxHz)
+	}
+	lo := int(math.Floor(float64(samplerate) / maxHz))
+	hi := int(math.Ceil(float64(samplerate) / minHz))
+	if lo < 1 {
+		lo = 1
+	}
+	if hi > len(cepstrum)/2 {
+		hi = len(cepstrum) / 2
+	}
+	best := -1
+	for q := lo; q <= hi && q+1 < len(cepstrum); q++ {
+		if cepstrum[q] > cepstrum[q-1] && cepstrum[q] >= cepstrum[q+1] &&
+			(best < 0 || cepstrum[q] > cepstrum[best]) {
+			best = q
+		}
+	}
+	if best < 0 {
+		return 0, 0, nil
+	}
+	// refine the quefrency by quadratic interpolation
+	a, b, c := cepstrum[best-1], cepstrum[best], cepstrum[best+1]
+	pos := float64(best)
+	if d := a - 2*b + c; d != 0 {
+		pos += 0.5 * (a - c) / d
+	}
+	return float64(samplerate) / pos, b, nil
+}
@@ -0,0 +1,67 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCepstralPitch(t *testing.T) {
+	c, err := NewCepstrum(testBufSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Free()
+	// a 200Hz tone rich in harmonics
+	signal := make([]float64, testBufSize)
+	for h := 1.; h <= 20; h++ {
+		for i, v := range tone(200*h, testSamplerate, testBufSize) {
+			signal[i] += v / h
+		}
+	}
+	in := NewSimpleBufferData(testBufSize, signal)
+	defer in.Free()
+	c.Do(in)
+	hz, strength, err := CepstralPitch(c.Buffer().Slice(), testSamplerate, 60, 1000)
+	if err != nil || math.Abs(hz-200) > 4 || strength <= 0 {
+		t.Errorf("cepstral pitch %vHz (%v, %v), want 200Hz", hz, strength, err)
+	}
+}
+
+func TestCepstralPitchRange(t *testing.T) {
+	cepstrum := make([]float64, testBufSize)
+	for _, r := range [][2]float64{{0, 1000}, {-60, 1000}, {500, 100}, {100, 100}} {
+		if _, _, err := CepstralPitch(cepstrum, testSamplerate, r[0], r[1]); err == nil {
+			t.Errorf("expected an error for the range %v to %vHz", r[0], r[1])
+		}
+	}
+}
+
+func TestCepstrumFree(t *testing.T) {
+	c, err := NewCepstrum(testBufSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Free()
+	if buf := c.Buffer(); buf != nil {
+		t.Errorf("got buffer %v after Free, want nil", buf)
+	}
+}
+
+func TestLifters(t *testing.T) {
+	cepstrum := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	low := LowLifter(cepstrum, 2)
+	high := HighLifter(cepstrum, 2)
+	wantLow := []float64{1, 2, 0, 0, 0, 0, 0, 8}
+	for i := range cepstrum {
+		if low[i] != wantLow[i] || low[i]+high[i] != cepstrum[i] {
+			t.Fatalf("LowLifter %v, HighLifter %v", low, high)
+		}
+	}
+	lifted := SinLifter([]float64{1, 1, 1}, 22)
+	if lifted[0] != 1 || lifted[1] <= lifted[0] || lifted[2] <= lifted[1] {
+		t.Errorf("SinLifter = %v", lifted)
+	}
+	if unlifted := SinLifter([]float64{1, 2}, 0); unlifted[0] != 1 || unlifted[1] != 2 {
+		t.Errorf("SinLifter with l = 0 gave %v, want the coefficients unchanged", unlifted)
+	}
+}
@@ -0,0 +1,234 @@
+package aubio
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// ChromaNorm selects how Chroma normalizes each frame.
+type ChromaNorm int
+
+const (
+	// ChromaNormMax scales each frame so its largest bin is 1.
+	ChromaNormMax ChromaNorm = iota
+	// ChromaNormSum scales each frame so its bins sum to 1.
+	ChromaNormSum
+	// ChromaNormEuclidean scales each frame to a unit euclidean norm.
+	ChromaNormEuclidean
+	// ChromaNormNone leaves the summed magnitudes untouched.
+	ChromaNormNone
+)
+
+const (
+	// ChromaBins is the number of pitch classes, starting at C.
+	ChromaBins = 12
+	// chromaWidth is the minimum standard deviation, in semitones, of
+	// the gaussian mapping each FFT bin onto the pitch classes.
+	chromaWidth = 0.5
+)
+
+// Chroma maps the spectrum of a PhaseVoc frame onto the 12 pitch
+// classes. It is implemented as a FilterBank with generated chroma
+// coefficients.
+type Chroma struct {
+	fb         *FilterBank
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+	tuning     float64
+	minOctave  int
+	maxOctave  int
+	norm       ChromaNorm
+}
+
+// NewChroma constructs a new Chroma object for PhaseVoc frames of
+// bufSize samples. It defaults to a 440Hz tuning, octaves 1 to 8 and
+// ChromaNormMax.
+// It is the Callers responsibility to call Free on the returned
+// Chroma object or leak memory.
+//     ch, err := NewChroma(bufSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer ch.Free()
+func NewChroma(bufSize, samplerate uint) (*Chroma, error) {
+	if bufSize < 2 || samplerate == 0 {
+		return nil, fmt.Errorf("invalid chroma parameters: bufSize %d, samplerate %d", bufSize, samplerate)
+	}
+	fb, err := newFilterBank(ChromaBins, bufSize)
+	if err != nil {
+		return nil, err
+	}
+	c := &Chroma{
+		fb:         fb,
+		buf:        NewSimpleBuffer(ChromaBins),
+		bufSize:    bufSize,
+		samplerate: samplerate,
+		tuning:     440,
+		minOctave:  1,
+		maxOctave:  8,
+		norm:       ChromaNormMax,
+	}
+	c.updateCoeffs()
+	return c, nil
+}
+
+// SetTuning sets the frequency in Hz of the reference A4.
+func (c *Chroma) SetTuning(hz float64) error {
+	if hz <= 0 {
+		return fmt.Errorf("invalid chroma tuning %vHz", hz)
+	}
+	if c.fb == nil {
+		return fmt.Errorf("failure setting tuning on freed Chroma")
+	}
+	c.tuning = hz
+	c.updateCoeffs()
+	return nil
+}
+
+// GetTuning returns the frequency in Hz of the reference A4.
+func (c *Chroma) GetTuning() float64 {
+	return c.tuning
+}
+
+// SetOctaveRange limits the spectrum taken into account to the
+// octaves min to max inclusive, numbered as in scientific pitch
+// notation where A4 is the reference tuning.
+func (c *Chroma) SetOctaveRange(min, max int) error {
+	if min > max {
+		return fmt.Errorf("invalid chroma octave range %d to %d", min, max)
+	}
+	if c.fb == nil {
+		return fmt.Errorf("failure setting octave range on freed Chroma")
+	}
+	c.minOctave = min
+	c.maxOctave = max
+	c.updateCoeffs()
+	return nil
+}
+
+// GetOctaveRange returns the lowest and highest octaves used.
+func (c *Chroma) GetOctaveRange() (int, int) {
+	return c.minOctave, c.maxOctave
+}
+
+// SetNorm sets the normalization applied to each frame.
+func (c *Chroma) SetNorm(norm ChromaNorm) {
+	c.norm = norm
+}
+
+// GetNorm returns the normalization applied to each frame.
+func (c *Chroma) GetNorm() ChromaNorm {
+	return c.norm
+}
+
+// Coeffs returns the chroma coefficients, one row of bufSize/2+1
+// weights per pitch class.
+func (c *Chroma) Coeffs() [][]float64 {
+	if c.fb == nil {
+		return nil
+	}
+	return c.fb.GetCoeffs()
+}
+
+// Buffer returns the 12 bin output buffer, indexed by pitch class
+// from C.
+func (c *Chroma) Buffer() *SimpleBuffer {
+	return c.buf
+}
+
+// Do computes the chroma of a spectrum from PhaseVoc.
+func (c *Chroma) Do(in *ComplexBuffer) {
+	if c.fb == nil {
+		log.Println("Called Do on empty Chroma. Maybe you called Free previously?")
+		return
+	}
+	c.fb.Do(in)
+	c.buf.SetData(normalizeChroma(c.fb.Buffer().Slice(), c.norm))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (c *Chroma) Free() {
+	if c.fb != nil {
+		c.fb.Free()
+		c.fb = nil
+	}
+	if c.buf != nil {
+		c.buf.Free()
+		c.buf = nil
+	}
+}
+
+func (c *Chroma) updateCoeffs() {
+	c.fb.SetCoeffs(chromaCoeffs(c.bufSize, c.samplerate, c.tuning, c.minOctave, c.maxOctave))
+}
+
+// chromaCoeffs spreads each FFT bin within the octave range over the
+// pitch classes with a gaussian centered on its pitch, wide enough to
+// cover the bin. The weights of each bin sum to 1.
+func chromaCoeffs(bufSize, samplerate uint, tuning float64, minOctave, maxOctave int) [][]float64 {
+	bins := int(bufSize/2 + 1)
+	coeffs := make([][]float64, ChromaBins)
+	for i := range coeffs {
+		coeffs[i] = make([]float64, bins)
+	}
+	binHz := float64(samplerate) / float64(bufSize)
+	// C of minOctave up to, but not including, C of maxOctave+1
+	low := float64(12 * (minOctave + 1))
+	high := float64(12 * (maxOctave + 2))
+	var weights [ChromaBins]float64
+	for k := 1; k < bins; k++ {
+		hz := float64(k) * binHz
+		pitch := 69 + 12*math.Log2(hz/tuning)
+		if pitch < low || pitch >= high {
+			continue
+		}
+		// the width in semitones of the bin, widest at low frequencies
+		width := 12 * math.Log2((hz+binHz/2)/math.Max(hz-binHz/2, binHz/2))
+		sigma := math.Max(chromaWidth, width/2)
+		var sum float64
+		for pc := range weights {
+			d := math.Mod(pitch-float64(pc), 12)
+			if d > 6 {
+				d -= 12
+			} else if d < -6 {
+				d += 12
+			}
+			weights[pc] = math.Exp(-0.5 * d * d / (sigma * sigma))
+			sum += weights[pc]
+		}
+		for pc, w := range weights {
+			coeffs[pc][k] = w / sum
+		}
+	}
+	return coeffs
+}
+
+func normalizeChroma(v []float64, norm ChromaNorm) []float64 {
+	var scale float64
+	switch norm {
+	case ChromaNormMax:
+		for _, x := range v {
+			scale = math.Max(scale, x)
+		}
+	case ChromaNormSum:
+		for _, x := range v {
+			scale += x
+		}
+	case ChromaNormEuclidean:
+		for _, x := range v {
+			scale += x * x
+		}
+		scale = math.Sqrt(scale)
+	default:
+		return v
+	}
+	if scale == 0 {
+		return v
+	}
+	for i := range v {
+		v[i] /= scale
+	}
+	return v
+}
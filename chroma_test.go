@@ -0,0 +1,117 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChromaCoeffs(t *testing.T) {
+	coeffs := chromaCoeffs(testBufSize, testSamplerate, 440, 1, 8)
+	binHz := float64(testSamplerate) / testBufSize
+	a4 := int(math.Round(440 / binHz))
+	best := 0
+	for pc := range coeffs {
+		if coeffs[pc][a4] > coeffs[best][a4] {
+			best = pc
+		}
+	}
+	if best != 9 {
+		t.Errorf("440Hz bin maps to pitch class %d, want 9", best)
+	}
+	var sum float64
+	for pc := range coeffs {
+		sum += coeffs[pc][a4]
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("weights of the 440Hz bin sum to %v, want 1", sum)
+	}
+	// DC and everything above B8 are outside the octave range
+	b8 := int(math.Ceil(MidiToFreq(12*10) / binHz))
+	for pc := range coeffs {
+		if coeffs[pc][0] != 0 || coeffs[pc][b8] != 0 {
+			t.Errorf("pitch class %d has weights outside the octave range", pc)
+		}
+	}
+}
+
+func TestChromaTuning(t *testing.T) {
+	// with A4 tuned a semitone up, 440Hz is heard as G#
+	coeffs := chromaCoeffs(testBufSize, testSamplerate, MidiToFreq(70), 1, 8)
+	a4 := int(math.Round(440 * testBufSize / testSamplerate))
+	if coeffs[8][a4] <= coeffs[9][a4] {
+		t.Errorf("440Hz bin should lean towards G# with a %vHz tuning", MidiToFreq(70))
+	}
+}
+
+func TestNormalizeChroma(t *testing.T) {
+	for _, tc := range []struct {
+		norm ChromaNorm
+		want float64
+	}{
+		{ChromaNormMax, 1},
+		{ChromaNormSum, 0.5},
+		{ChromaNormEuclidean, 1 / math.Sqrt2},
+		{ChromaNormNone, 2},
+	} {
+		v := normalizeChroma([]float64{2, 2, 0}, tc.norm)
+		if math.Abs(v[0]-tc.want) > 1e-9 {
+			t.Errorf("norm %d: got %v, want %v", tc.norm, v[0], tc.want)
+		}
+	}
+	if v := normalizeChroma(make([]float64, ChromaBins), ChromaNormMax); v[0] != 0 {
+		t.Errorf("silent frame normalized to %v", v)
+	}
+}
+
+func TestChroma(t *testing.T) {
+	pv, err := NewPhaseVoc(testBufSize, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pv.Free()
+	ch, err := NewChroma(testBufSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ch.Free()
+	// an E3 major triad
+	signal := tone(MidiToFreq(52), testSamplerate, testSamplerate/2)
+	for _, midi := range []float64{56, 59} {
+		for i, v := range tone(MidiToFreq(midi), testSamplerate, testSamplerate/2) {
+			signal[i] += v
+		}
+	}
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		buf.SetData(signal[i : i+testHopSize])
+		pv.Do(buf)
+		ch.Do(pv.Grain())
+	}
+	chroma := ch.Buffer().Slice()
+	for _, pc := range []int{4, 8, 11} {
+		for _, other := range []int{0, 2, 5, 7, 9} {
+			if chroma[pc] <= chroma[other] {
+				t.Errorf("chroma %v: pitch class %d not above %d", chroma, pc, other)
+			}
+		}
+	}
+}
+
+func TestChromaFree(t *testing.T) {
+	ch, err := NewChroma(testBufSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch.Free()
+	// must not crash once freed
+	if err := ch.SetTuning(442); err == nil {
+		t.Error("expected an error setting the tuning after Free")
+	}
+	if err := ch.SetOctaveRange(2, 6); err == nil {
+		t.Error("expected an error setting the octave range after Free")
+	}
+	if ch.Coeffs() != nil {
+		t.Error("expected no coefficients after Free")
+	}
+}
@@ -0,0 +1,265 @@
+package eval
+
+import (
+	"math"
+)
+
+const (
+	// DefaultPhaseThreshold is the continuity phase tolerance, as a
+	// fraction of the reference inter beat interval.
+	DefaultPhaseThreshold = 0.175
+	// DefaultPeriodThreshold is the continuity period tolerance, as a
+	// fraction of the reference inter beat interval.
+	DefaultPeriodThreshold = 0.175
+	// DefaultPScoreThreshold is the P-score window, as a fraction of the
+	// median reference inter beat interval.
+	DefaultPScoreThreshold = 0.2
+	// DefaultCemgilSigma is the standard deviation, in seconds, of the
+	// Gaussian error window used by Cemgil.
+	DefaultCemgilSigma = 0.04
+)
+
+// BeatScores holds the standard beat tracking scores.
+type BeatScores struct {
+	FMeasure float64
+	// CMLc and CMLt are the longest continuous and the total proportion
+	// of correct beats at the correct metrical level.
+	CMLc float64
+	CMLt float64
+	// AMLc and AMLt also allow the off-beat, double and half tempo
+	// metrical levels.
+	AMLc       float64
+	AMLt       float64
+	PScore     float64
+	Cemgil     float64
+	CemgilBest float64
+}
+
+// Beats computes every beat tracking score using the default
+// parameters.
+func Beats(detected, reference []float64) BeatScores {
+	c := Continuity(detected, reference, DefaultPhaseThreshold, DefaultPeriodThreshold)
+	cemgil, best := Cemgil(detected, reference, DefaultCemgilSigma)
+	return BeatScores{
+		FMeasure:   Match(detected, reference, DefaultBeatWindow).FMeasure,
+		CMLc:       c.CMLc,
+		CMLt:       c.CMLt,
+		AMLc:       c.AMLc,
+		AMLt:       c.AMLt,
+		PScore:     PScore(detected, reference, DefaultPScoreThreshold),
+		Cemgil:     cemgil,
+		CemgilBest: best,
+	}
+}
+
+// ContinuityScores holds the continuity based beat tracking scores.
+type ContinuityScores struct {
+	CMLc float64
+	CMLt float64
+	AMLc float64
+	AMLt float64
+}
+
+// Continuity computes the continuity based scores of Hainsworth and
+// Klapuri, as mir_eval does. A detected beat is correct when it lies
+// within phaseThreshold reference intervals of the nearest reference
+// beat, no earlier detected beat was matched to that reference beat,
+// the interval leading to it is within periodThreshold of the
+// reference interval, and the previous detected beat lies within
+// phaseThreshold of the previous reference beat. The first beats are
+// checked against the following intervals instead.
+func Continuity(detected, reference []float64, phaseThreshold, periodThreshold float64) ContinuityScores {
+	det := sorted(detected)
+	ref := sorted(reference)
+	if len(det) < 2 || len(ref) < 2 {
+		return ContinuityScores{}
+	}
+	var scores ContinuityScores
+	for i, variation := range beatVariations(ref) {
+		if len(variation) < 2 {
+			continue
+		}
+		used := make([]bool, len(variation))
+		correct, longest, run := 0, 0, 0
+		for m := range det {
+			if continuityCorrect(det, variation, used, m, phaseThreshold, periodThreshold) {
+				correct++
+				run++
+				if run > longest {
+					longest = run
+				}
+			} else {
+				run = 0
+			}
+		}
+		n := float64(max(len(det), len(variation)))
+		c, t := float64(longest)/n, float64(correct)/n
+		if i == 0 {
+			scores.CMLc, scores.CMLt = c, t
+		}
+		scores.AMLc = math.Max(scores.AMLc, c)
+		scores.AMLt = math.Max(scores.AMLt, t)
+	}
+	return scores
+}
+
+// continuityCorrect returns if the detected beat det[m] is correct,
+// and marks the reference beat it matched as used.
+func continuityCorrect(det, ref []float64, used []bool, m int, phaseThreshold, periodThreshold float64) bool {
+	nearest := nearestIndex(ref, det[m])
+	if used[nearest] {
+		return false
+	}
+	var refInterval, detInterval float64
+	previous := true
+	if m == 0 || nearest == 0 {
+		if nearest+1 < len(ref) {
+			refInterval = ref[nearest+1] - ref[nearest]
+		} else {
+			refInterval = ref[nearest] - ref[nearest-1]
+		}
+		if m+1 < len(det) {
+			detInterval = det[m+1] - det[m]
+		} else {
+			detInterval = det[m] - det[m-1]
+		}
+	} else {
+		refInterval = ref[nearest] - ref[nearest-1]
+		detInterval = det[m] - det[m-1]
+		if refInterval != 0 {
+			previous = math.Abs(det[m-1]-ref[nearest-1])/refInterval < phaseThreshold
+		}
+	}
+	if refInterval == 0 {
+		return false
+	}
+	phase := math.Abs(det[m]-ref[nearest]) / refInterval
+	period := math.Abs(1 - detInterval/refInterval)
+	if phase < phaseThreshold && period < periodThreshold && previous {
+		used[nearest] = true
+		return true
+	}
+	return false
+}
+
+// PScore computes the P-score of McKinney et al: the correlation of
+// the detected and reference impulse trains, sampled at 100Hz, within
+// threshold median reference intervals of zero lag.
+func PScore(detected, reference []float64, threshold float64) float64 {
+	if len(detected) < 2 || len(reference) < 2 {
+		return 0
+	}
+	const samplerate = 100
+	ref := sorted(reference)
+	offset := math.Min(minOf(detected), ref[0])
+	intervals := make([]float64, len(ref)-1)
+	for i := range intervals {
+		intervals[i] = ref[i+1] - ref[i]
+	}
+	win := int(math.Round(threshold * median(intervals) * samplerate))
+	index := func(events []float64) map[int]bool {
+		idx := map[int]bool{}
+		for _, e := range events {
+			idx[int((e-offset)*samplerate)] = true
+		}
+		return idx
+	}
+	detIdx, refIdx := index(detected), index(ref)
+	total := 0
+	for r := range refIdx {
+		for lag := -win; lag <= win; lag++ {
+			if detIdx[r+lag] {
+				total++
+			}
+		}
+	}
+	return float64(total) / float64(max(len(detected), len(reference)))
+}
+
+// Cemgil computes the Cemgil et al accuracy, which weights the error
+// of each reference beat to its nearest detected beat with a Gaussian
+// of standard deviation sigma. The second value is the best accuracy
+// over the off-beat, double and half tempo metrical levels.
+func Cemgil(detected, reference []float64, sigma float64) (float64, float64) {
+	if len(detected) == 0 || len(reference) == 0 {
+		return 0, 0
+	}
+	det := sorted(detected)
+	var first, best float64
+	for i, variation := range beatVariations(sorted(reference)) {
+		var acc float64
+		for _, beat := range variation {
+			diff := math.Abs(beat - det[nearestIndex(det, beat)])
+			acc += math.Exp(-diff * diff / (2 * sigma * sigma))
+		}
+		acc /= 0.5 * float64(len(det)+len(variation))
+		if i == 0 {
+			first = acc
+		}
+		best = math.Max(best, acc)
+	}
+	return first, best
+}
+
+// beatVariations returns the reference beats at the annotated
+// metrical level followed by the off-beat, double tempo and both
+// half tempo variations.
+func beatVariations(ref []float64) [][]float64 {
+	var offBeat, double, halfOdd, halfEven []float64
+	for i, b := range ref {
+		double = append(double, b)
+		if i+1 < len(ref) {
+			mid := (b + ref[i+1]) / 2
+			offBeat = append(offBeat, mid)
+			double = append(double, mid)
+		}
+		if i%2 == 0 {
+			halfOdd = append(halfOdd, b)
+		} else {
+			halfEven = append(halfEven, b)
+		}
+	}
+	return [][]float64{ref, offBeat, double, halfOdd, halfEven}
+}
+
+// nearestIndex returns the index of the element of the sorted slice
+// events closest to t.
+func nearestIndex(events []float64, t float64) int {
+	lo, hi := 0, len(events)-1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if events[mid] < t {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo > 0 && t-events[lo-1] <= events[lo]-t {
+		return lo - 1
+	}
+	return lo
+}
+
+func minOf(events []float64) float64 {
+	m := math.Inf(1)
+	for _, e := range events {
+		m = math.Min(m, e)
+	}
+	return m
+}
+
+func median(values []float64) float64 {
+	s := sorted(values)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,87 @@
+package eval
+
+import (
+	"testing"
+)
+
+func TestBeatsPerfect(t *testing.T) {
+	ref := clickTrack(120, 0.5, 30)
+	s := Beats(ref, ref)
+	for name, v := range map[string]float64{
+		"FMeasure": s.FMeasure, "CMLc": s.CMLc, "CMLt": s.CMLt, "AMLc": s.AMLc,
+		"AMLt": s.AMLt, "PScore": s.PScore, "Cemgil": s.Cemgil, "CemgilBest": s.CemgilBest,
+	} {
+		if !near(v, 1) {
+			t.Errorf("%s got %v, want 1", name, v)
+		}
+	}
+}
+
+func TestBeatsMetricalLevels(t *testing.T) {
+	ref := clickTrack(120, 0.5, 30)
+	for name, det := range map[string][]float64{
+		"off-beat": clickTrack(120, 0.75, 30),
+		"double":   clickTrack(240, 0.5, 30),
+		"half":     clickTrack(60, 0.5, 30),
+	} {
+		s := Beats(det, ref)
+		if s.CMLt > 0.1 {
+			t.Errorf("%s: CMLt got %v, want ~0", name, s.CMLt)
+		}
+		if s.AMLt < 0.95 {
+			t.Errorf("%s: AMLt got %v, want ~1", name, s.AMLt)
+		}
+		if s.CemgilBest < 0.95 {
+			t.Errorf("%s: CemgilBest got %v, want ~1", name, s.CemgilBest)
+		}
+	}
+}
+
+func TestContinuityBreak(t *testing.T) {
+	ref := clickTrack(120, 0.5, 30)
+	det := append([]float64(nil), ref...)
+	// shift the middle beat off by a quarter beat, splitting the run
+	mid := len(det) / 2
+	det[mid] += 0.125
+	c := Continuity(det, ref, DefaultPhaseThreshold, DefaultPeriodThreshold)
+	if c.CMLt >= 1 || c.CMLt < 0.9 {
+		t.Errorf("CMLt got %v, want just under 1", c.CMLt)
+	}
+	if c.CMLc > 0.6 {
+		t.Errorf("CMLc got %v, want about half", c.CMLc)
+	}
+}
+
+func TestWrongTempo(t *testing.T) {
+	ref := clickTrack(120, 0.5, 30)
+	det := clickTrack(97, 0.5, 30)
+	s := Beats(det, ref)
+	if s.AMLt > 0.3 || s.FMeasure > 0.5 {
+		t.Errorf("unrelated tempo scored too well: %+v", s)
+	}
+}
+
+func TestContinuityMirEval(t *testing.T) {
+	ref := clickTrack(120, 0.5, 6)
+	// the expected values follow mir_eval.beat.continuity
+	late := append([]float64(nil), ref...)
+	late[5] += 0.1
+	// the seventh beat is on time with a fair interval, but follows a
+	// beat too far from its reference to count
+	previous := append([]float64(nil), ref...)
+	previous[5] += 0.09
+	previous[6] += 0.005
+	for name, tc := range map[string]struct {
+		det        []float64
+		cmlc, cmlt float64
+	}{
+		"doubled":  {append(append([]float64(nil), ref...), 3.04), 6. / 12, 11. / 12},
+		"late":     {late, 5. / 11, 9. / 11},
+		"previous": {previous, 5. / 11, 9. / 11},
+	} {
+		c := Continuity(tc.det, ref, DefaultPhaseThreshold, DefaultPeriodThreshold)
+		if !near(c.CMLc, tc.cmlc) || !near(c.CMLt, tc.cmlt) || !near(c.AMLc, tc.cmlc) || !near(c.AMLt, tc.cmlt) {
+			t.Errorf("%s: got %+v, want CMLc %v and CMLt %v", name, c, tc.cmlc, tc.cmlt)
+		}
+	}
+}
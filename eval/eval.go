@@ -0,0 +1,90 @@
+// Package eval scores detected onset and beat times against reference
+// annotations.
+//
+// All times are in seconds. The beat tracking scores follow the
+// definitions used by mir_eval (https://github.com/craffel/mir_eval),
+// without its trimming of the first five seconds; use Trim for that.
+package eval
+
+import (
+	"sort"
+)
+
+const (
+	// DefaultOnsetWindow is the usual tolerance when matching onsets.
+	DefaultOnsetWindow = 0.05
+	// DefaultBeatWindow is the usual tolerance when matching beats.
+	DefaultBeatWindow = 0.07
+)
+
+// Scores holds the result of matching detected events to reference ones.
+type Scores struct {
+	Precision      float64
+	Recall         float64
+	FMeasure       float64
+	TruePositives  int
+	FalsePositives int
+	FalseNegatives int
+}
+
+// Match pairs each reference event with at most one detected event
+// lying within window seconds of it, and scores the result.
+//
+// The events are paired greedily in time order rather than with the
+// bipartite matching of mir_eval. As every event has a window of the
+// same width, the greedy pairing is also a maximum one, so the scores
+// are the same.
+func Match(detected, reference []float64, window float64) Scores {
+	det := sorted(detected)
+	ref := sorted(reference)
+	hits := 0
+	for i, j := 0, 0; i < len(ref) && j < len(det); {
+		switch {
+		case ref[i]-det[j] > window:
+			j++
+		case det[j]-ref[i] > window:
+			i++
+		default:
+			hits++
+			i++
+			j++
+		}
+	}
+	s := Scores{
+		TruePositives:  hits,
+		FalsePositives: len(det) - hits,
+		FalseNegatives: len(ref) - hits,
+	}
+	if len(det) > 0 {
+		s.Precision = float64(hits) / float64(len(det))
+	}
+	if len(ref) > 0 {
+		s.Recall = float64(hits) / float64(len(ref))
+	}
+	if s.Precision+s.Recall > 0 {
+		s.FMeasure = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+	return s
+}
+
+// Onsets scores detected onsets with the DefaultOnsetWindow.
+func Onsets(detected, reference []float64) Scores {
+	return Match(detected, reference, DefaultOnsetWindow)
+}
+
+// Trim returns the events at or after minTime.
+func Trim(events []float64, minTime float64) []float64 {
+	var out []float64
+	for _, e := range events {
+		if e >= minTime {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func sorted(events []float64) []float64 {
+	out := append([]float64(nil), events...)
+	sort.Float64s(out)
+	return out
+}
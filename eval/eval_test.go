@@ -0,0 +1,129 @@
+package eval
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// clickTrack returns the beat times of a click track at bpm starting
+// at offset and lasting duration seconds.
+func clickTrack(bpm, offset, duration float64) []float64 {
+	var beats []float64
+	for t := offset; t < duration; t += 60 / bpm {
+		beats = append(beats, t)
+	}
+	return beats
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMatch(t *testing.T) {
+	ref := clickTrack(120, 0.5, 10)
+	jittered := make([]float64, len(ref))
+	for i, b := range ref {
+		jittered[i] = b + 0.03*math.Sin(float64(i))
+	}
+	if s := Onsets(jittered, ref); !near(s.FMeasure, 1) {
+		t.Errorf("jittered onsets got %+v, want a perfect score", s)
+	}
+
+	extra := append(append([]float64(nil), ref...), clickTrack(120, 0.75, 10)...)
+	s := Onsets(extra, ref)
+	if !near(s.Precision, 0.5) || !near(s.Recall, 1) || s.FalsePositives != len(ref) {
+		t.Errorf("doubled onsets got %+v, want precision 0.5 and recall 1", s)
+	}
+
+	s = Match(ref[:len(ref)/2], ref, DefaultOnsetWindow)
+	if !near(s.Precision, 1) || s.FalseNegatives != len(ref)-len(ref)/2 {
+		t.Errorf("missing onsets got %+v", s)
+	}
+
+	if s := Onsets(nil, ref); s.FMeasure != 0 {
+		t.Errorf("no onsets got %+v", s)
+	}
+}
+
+// maxMatching returns the size of a maximum bipartite matching between
+// the events within window of each other, found with augmenting paths
+// like mir_eval.
+func maxMatching(detected, reference []float64, window float64) int {
+	owner := make([]int, len(detected))
+	for j := range owner {
+		owner[j] = -1
+	}
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for j, d := range detected {
+			if seen[j] || math.Abs(d-reference[i]) > window {
+				continue
+			}
+			seen[j] = true
+			if owner[j] < 0 || augment(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	n := 0
+	for i := range reference {
+		if augment(i, make([]bool, len(detected))) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestMatchIsMaximum(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	events := func(n int) []float64 {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = rng.Float64()
+		}
+		return out
+	}
+	// annotations dense enough for most events to compete for a pair
+	for trial := 0; trial < 200; trial++ {
+		det, ref := events(5+rng.Intn(20)), events(5+rng.Intn(20))
+		want := maxMatching(det, ref, DefaultOnsetWindow)
+		if got := Match(det, ref, DefaultOnsetWindow).TruePositives; got != want {
+			t.Fatalf("matched %d of %v and %v, want %d", got, det, ref, want)
+		}
+	}
+}
+
+func TestLoadEvents(t *testing.T) {
+	for name, text := range map[string]string{
+		"plain":     "0.5\n1.0\n\n# comment\n1.5\n",
+		"csv":       "time,label\n1.0,b\n0.5,a\n1.5,c\n",
+		"audacity":  "0.5\t0.5\tkick\n1.0\t1.0\tsnare\n1.5\t1.5\tkick\n",
+		"empty":     "0.5,a\n,,\n1.0,b\n;\n1.5,c\n",
+		"commented": "# exported labels\n\ntime,label\n0.5,a\n1.0,b\n1.5,c\n",
+	} {
+		events, err := LoadEvents(strings.NewReader(text))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		want := []float64{0.5, 1.0, 1.5}
+		if len(events) != len(want) {
+			t.Errorf("%s: got %v, want %v", name, events, want)
+			continue
+		}
+		for i := range want {
+			if events[i] != want[i] {
+				t.Errorf("%s: got %v, want %v", name, events, want)
+			}
+		}
+	}
+	for _, text := range []string{"0.5\nabc\n", "time\nlabel\n0.5\n"} {
+		if _, err := LoadEvents(strings.NewReader(text)); err == nil {
+			t.Errorf("expected an error for a non numeric event time in %q", text)
+		}
+	}
+}
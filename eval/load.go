@@ -0,0 +1,62 @@
+package eval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadEvents reads event times in seconds from a plain text or CSV
+// label file. The first field of every line is the event time; any
+// further fields, such as an end time or a label, are ignored. Fields
+// may be separated by commas, semicolons, tabs or spaces. Blank lines,
+// lines holding only separators, lines starting with '#' and a non
+// numeric header as the first other line are skipped.
+// The returned times are sorted.
+func LoadEvents(r io.Reader) ([]float64, error) {
+	var events []float64
+	scanner := bufio.NewScanner(r)
+	header := true
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == ';'
+		})
+		if len(fields) == 0 {
+			continue
+		}
+		first := header
+		header = false
+		t, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			if first {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid event time %q", line, fields[0])
+		}
+		events = append(events, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Float64s(events)
+	return events, nil
+}
+
+// LoadEventsFile reads event times from the label file at path.
+// See LoadEvents for the accepted formats.
+func LoadEventsFile(path string) ([]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadEvents(f)
+}
@@ -21,7 +21,7 @@ import (
 
 func main() {
 	src := util.Init()
-	pitch := aubio.NewPitch(aubio.PitchDefault, uint(*util.Bufsize), uint(*util.Blocksize), uint(*util.Samplerate))
+	pitch := aubio.PitchOrDie(aubio.PitchDefault, uint(*util.Bufsize), uint(*util.Blocksize), uint(*util.Samplerate))
 	//pitch.SetUnit(aubio.PitchOutDefault)
 	pitch.SetTolerance(0.7)
 	p := aubio.NewSimplePipeline(src, nil, uint(*util.Bufsize))
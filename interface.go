@@ -20,3 +20,26 @@ type ReverseComplexAnalyzer interface {
 }
 
 type ProcessFunc func(input *SimpleBuffer)
+
+// BeatPredictor is implemented by the beat sources that can predict
+// upcoming beats, such as BeatClock and TapTempo.
+type BeatPredictor interface {
+	// Now returns the current time of the source, in seconds.
+	Now() float64
+	// Running returns if the source knows a tempo to predict beats
+	// from.
+	Running() bool
+	// Ticked returns if the virtual metronome of the source ticked
+	// during its latest step, Lookahead seconds before a predicted
+	// beat.
+	Ticked() bool
+	// BPM returns the current tempo in beats per minute, or 0 when
+	// no tempo is known.
+	BPM() float64
+	// Phase returns the position of Now between the previous beat and
+	// the next one, from 0 to 1.
+	Phase() float64
+	// NextBeats returns the predicted times of the next n beats after
+	// Now, in seconds.
+	NextBeats(n int) []float64
+}
@@ -0,0 +1,248 @@
+package aubio
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+)
+
+// KeyMode is the mode of a musical key.
+type KeyMode int
+
+const (
+	Major KeyMode = iota
+	Minor
+)
+
+func (m KeyMode) String() string {
+	if m == Minor {
+		return "minor"
+	}
+	return "major"
+}
+
+// KeyProfile selects the key templates pitch class profiles are
+// correlated against.
+type KeyProfile int
+
+const (
+	// KeyProfileKrumhansl uses the Krumhansl-Kessler probe tone ratings.
+	KeyProfileKrumhansl KeyProfile = iota
+	// KeyProfileTemperley uses Temperley's Kostka-Payne corpus profiles.
+	KeyProfileTemperley
+)
+
+// keyTemplates holds the major and minor templates of each KeyProfile,
+// indexed by pitch class relative to the tonic.
+var keyTemplates = map[KeyProfile][2][ChromaBins]float64{
+	KeyProfileKrumhansl: {
+		{6.35, 2.23, 3.48, 2.33, 4.38, 4.09, 2.52, 5.19, 2.39, 3.66, 2.29, 2.88},
+		{6.33, 2.68, 3.52, 5.38, 2.60, 3.53, 2.54, 4.75, 3.98, 2.69, 3.34, 3.17},
+	},
+	KeyProfileTemperley: {
+		{0.748, 0.060, 0.488, 0.082, 0.670, 0.460, 0.096, 0.715, 0.104, 0.366, 0.057, 0.400},
+		{0.712, 0.084, 0.474, 0.618, 0.049, 0.460, 0.105, 0.747, 0.404, 0.067, 0.133, 0.330},
+	},
+}
+
+var pitchClassNames = [ChromaBins]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// Key is a musical key along with how well a pitch class profile
+// correlates with its template.
+type Key struct {
+	// Tonic is the pitch class of the tonic, 0 being C.
+	Tonic int
+	Mode  KeyMode
+	// Correlation is the Pearson correlation between the profile and
+	// the key template, between -1 and 1.
+	Correlation float64
+}
+
+func (k Key) String() string {
+	return pitchClassNames[k.Tonic] + " " + k.Mode.String()
+}
+
+// KeyEstimate is the outcome of a key estimation.
+type KeyEstimate struct {
+	Key
+	// RunnerUp is the second best matching key.
+	RunnerUp Key
+	// Confidence is the difference between the correlations of Key
+	// and RunnerUp. Values close to 0 mean the key is ambiguous.
+	Confidence float64
+}
+
+// MatchKey correlates a 12 bin pitch class profile, indexed from C,
+// against the 24 major and minor keys of profile.
+func MatchKey(chroma []float64, profile KeyProfile) (KeyEstimate, error) {
+	if len(chroma) != ChromaBins {
+		return KeyEstimate{}, fmt.Errorf("pitch class profile has %d bins, expected %d", len(chroma), ChromaBins)
+	}
+	templates, ok := keyTemplates[profile]
+	if !ok {
+		return KeyEstimate{}, fmt.Errorf("unknown key profile %d", profile)
+	}
+	keys := make([]Key, 0, 2*ChromaBins)
+	for _, mode := range []KeyMode{Major, Minor} {
+		for tonic := 0; tonic < ChromaBins; tonic++ {
+			var rotated [ChromaBins]float64
+			for pc := range rotated {
+				rotated[pc] = templates[mode][(pc-tonic+ChromaBins)%ChromaBins]
+			}
+			keys = append(keys, Key{Tonic: tonic, Mode: mode, Correlation: pearson(chroma, rotated[:])})
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].Correlation > keys[j].Correlation })
+	return KeyEstimate{
+		Key:        keys[0],
+		RunnerUp:   keys[1],
+		Confidence: keys[0].Correlation - keys[1].Correlation,
+	}, nil
+}
+
+func pearson(a, b []float64) float64 {
+	var ma, mb float64
+	for i := range a {
+		ma += a[i]
+		mb += b[i]
+	}
+	ma /= float64(len(a))
+	mb /= float64(len(b))
+	var cov, va, vb float64
+	for i := range a {
+		cov += (a[i] - ma) * (b[i] - mb)
+		va += (a[i] - ma) * (a[i] - ma)
+		vb += (b[i] - mb) * (b[i] - mb)
+	}
+	if va == 0 || vb == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(va*vb)
+}
+
+// KeyTracker estimates the key of a live stream from a running
+// average of its chroma.
+type KeyTracker struct {
+	pv      *PhaseVoc
+	chroma  *Chroma
+	hop     float64
+	profile []float64
+	frames  int
+
+	// Profile selects the key templates. Defaults to KeyProfileKrumhansl.
+	Profile KeyProfile
+	// Decay is the time constant in seconds of the exponential moving
+	// average of the chroma. When 0 every frame is weighted equally.
+	// Defaults to 10s.
+	Decay float64
+}
+
+// NewKeyTracker constructs a new KeyTracker.
+// It is the Callers responsibility to call Free on the returned
+// KeyTracker object or leak memory.
+//     kt, err := NewKeyTracker(bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer kt.Free()
+func NewKeyTracker(bufSize, hopSize, samplerate uint) (*KeyTracker, error) {
+	pv, err := NewPhaseVoc(bufSize, hopSize)
+	if err != nil {
+		return nil, err
+	}
+	chroma, err := NewChroma(bufSize, samplerate)
+	if err != nil {
+		pv.Free()
+		return nil, err
+	}
+	chroma.SetNorm(ChromaNormSum)
+	return &KeyTracker{
+		pv:      pv,
+		chroma:  chroma,
+		hop:     float64(hopSize) / float64(samplerate),
+		profile: make([]float64, ChromaBins),
+		Profile: KeyProfileKrumhansl,
+		Decay:   10,
+	}, nil
+}
+
+// Chroma returns the underlying Chroma object, for instance to
+// change its tuning or octave range.
+func (kt *KeyTracker) Chroma() *Chroma {
+	return kt.chroma
+}
+
+// Do adds a hopSize input Buffer to the averaged pitch class profile.
+func (kt *KeyTracker) Do(input *SimpleBuffer) {
+	if kt.pv == nil {
+		log.Println("Called Do on empty KeyTracker. Maybe you called Free previously?")
+		return
+	}
+	kt.pv.Do(input)
+	kt.chroma.Do(kt.pv.Grain())
+	kt.frames++
+	alpha := 1 / float64(kt.frames)
+	if kt.Decay > 0 {
+		alpha = math.Max(alpha, math.Min(kt.hop/kt.Decay, 1))
+	}
+	for i, v := range kt.chroma.Buffer().Slice() {
+		kt.profile[i] += alpha * (v - kt.profile[i])
+	}
+}
+
+// PitchClassProfile returns a copy of the averaged pitch class
+// profile, indexed from C.
+func (kt *KeyTracker) PitchClassProfile() []float64 {
+	return append([]float64(nil), kt.profile...)
+}
+
+// Estimate returns the key matching the averaged pitch class profile.
+func (kt *KeyTracker) Estimate() (KeyEstimate, error) {
+	return MatchKey(kt.profile, kt.Profile)
+}
+
+// Reset forgets the averaged pitch class profile.
+func (kt *KeyTracker) Reset() {
+	kt.frames = 0
+	for i := range kt.profile {
+		kt.profile[i] = 0
+	}
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (kt *KeyTracker) Free() {
+	if kt.pv != nil {
+		kt.pv.Free()
+		kt.pv = nil
+	}
+	if kt.chroma != nil {
+		kt.chroma.Free()
+		kt.chroma = nil
+	}
+}
+
+// EstimateKey reads the whole of src and estimates its key from its
+// average chroma, using a window four times the block size of src.
+func EstimateKey(src *Source, profile KeyProfile) (KeyEstimate, error) {
+	kt, err := NewKeyTracker(4*src.BlockSize(), src.BlockSize(), src.Samplerate())
+	if err != nil {
+		return KeyEstimate{}, err
+	}
+	defer kt.Free()
+	kt.Profile = profile
+	kt.Decay = 0
+	buf := NewSimpleBuffer(src.BlockSize())
+	defer buf.Free()
+	for {
+		n := src.Do(buf)
+		if n == 0 {
+			break
+		}
+		kt.Do(buf)
+		if n < src.BlockSize() {
+			break
+		}
+	}
+	return kt.Estimate()
+}
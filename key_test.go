@@ -0,0 +1,118 @@
+package aubio
+
+import (
+	"testing"
+)
+
+var keyTests = []struct {
+	name        string
+	progression [][]float64
+	want        string
+}{
+	// I IV V I
+	{"C major", [][]float64{{60, 64, 67}, {65, 69, 72}, {67, 71, 74}, {60, 64, 67}}, "C major"},
+	// I vi ii V I
+	{"D major", [][]float64{{62, 66, 69}, {59, 62, 66}, {64, 67, 71}, {69, 73, 76}, {62, 66, 69}}, "D major"},
+	// i iv V i
+	{"A minor", [][]float64{{57, 60, 64}, {62, 65, 69}, {64, 68, 71}, {57, 60, 64}}, "A minor"},
+	// i VI iv V i
+	{"F# minor", [][]float64{{54, 57, 61}, {62, 66, 69}, {59, 62, 66}, {61, 65, 68}, {54, 57, 61}}, "F# minor"},
+}
+
+func TestMatchKey(t *testing.T) {
+	for _, profile := range []KeyProfile{KeyProfileKrumhansl, KeyProfileTemperley} {
+		for _, tc := range keyTests {
+			chroma := make([]float64, ChromaBins)
+			for _, chord := range tc.progression {
+				for _, midi := range chord {
+					chroma[int(midi)%ChromaBins]++
+				}
+			}
+			got, err := MatchKey(chroma, profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tc.want {
+				t.Errorf("profile %d, %s: got %s (runner up %s)", profile, tc.name, got, got.RunnerUp)
+			}
+			if got.Confidence < 0 || got.RunnerUp == got.Key {
+				t.Errorf("profile %d, %s: bad runner up %+v", profile, tc.name, got)
+			}
+		}
+	}
+	if _, err := MatchKey(make([]float64, 3), KeyProfileKrumhansl); err == nil {
+		t.Error("expected an error for a short profile")
+	}
+}
+
+// renderProgression synthesizes half a second of each chord, a whole
+// number of hops long.
+func renderProgression(progression [][]float64) []float64 {
+	n := testSamplerate / 2 / testHopSize * testHopSize
+	var signal []float64
+	for _, chord := range progression {
+		part := make([]float64, n)
+		for _, midi := range chord {
+			for i, v := range tone(MidiToFreq(midi), testSamplerate, n) {
+				part[i] += v / 3
+			}
+		}
+		signal = append(signal, part...)
+	}
+	return signal
+}
+
+func TestKeyTracker(t *testing.T) {
+	for _, tc := range keyTests {
+		kt, err := NewKeyTracker(4*testHopSize, testHopSize, testSamplerate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := NewSimpleBuffer(testHopSize)
+		signal := renderProgression(tc.progression)
+		for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+			buf.SetData(signal[i : i+testHopSize])
+			kt.Do(buf)
+		}
+		got, err := kt.Estimate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != tc.want {
+			t.Errorf("%s: got %s (runner up %s), profile %v", tc.name, got, got.RunnerUp, kt.PitchClassProfile())
+		}
+		buf.Free()
+		kt.Free()
+	}
+}
+
+func TestEstimateKey(t *testing.T) {
+	for _, tc := range keyTests {
+		path := t.TempDir() + "/progression.wav"
+		sink, err := OpenSink(path, testSamplerate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signal := renderProgression(tc.progression)
+		buf := NewSimpleBuffer(testHopSize)
+		for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+			buf.SetData(signal[i : i+testHopSize])
+			sink.Do(buf, testHopSize)
+		}
+		buf.Free()
+		sink.Close()
+
+		src, err := OpenSource(path, testSamplerate, testHopSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := EstimateKey(src, KeyProfileKrumhansl)
+		src.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != tc.want {
+			t.Errorf("%s: got %s (runner up %s)", tc.name, got, got.RunnerUp)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package aubio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OnsetMethod names an onset detection function understood by
+// new_aubio_onset and new_aubio_tempo.
+// See: https://github.com/aubio/aubio/blob/master/src/spectral/specdesc.h
+type OnsetMethod string
+
+const (
+	// Default onset detection function (currently HFC)
+	OnsetDefault OnsetMethod = "default"
+	// Energy based onset detection function
+	Energy OnsetMethod = "energy"
+	// High Frequency Content onset detection function
+	HFC OnsetMethod = "hfc"
+	// Complex Domain Method onset detection function
+	Complex OnsetMethod = "complex"
+	// Phase based Method onset detection function
+	Phase OnsetMethod = "phase"
+	// Weighted Phase Deviation onset detection function
+	WPhase OnsetMethod = "wphase"
+	// Spectral difference method onset detection function
+	SpecDiff OnsetMethod = "specdiff"
+	// Kullback-Liebler onset detection function
+	KL OnsetMethod = "kl"
+	// Modified Kullback-Liebler onset detection function
+	MKL OnsetMethod = "mkl"
+	// Spectral Flux
+	SpecFlux OnsetMethod = "specflux"
+
+	// Deprecated: K1 was misspelled, use KL.
+	K1 = KL
+	// Deprecated: MK1 was misspelled, use MKL.
+	MK1 = MKL
+)
+
+// AllOnsetMethods returns every supported OnsetMethod.
+func AllOnsetMethods() []OnsetMethod {
+	return []OnsetMethod{OnsetDefault, Energy, HFC, Complex, Phase, WPhase,
+		SpecDiff, KL, MKL, SpecFlux}
+}
+
+// ParseOnsetMethod returns the OnsetMethod named by s.
+func ParseOnsetMethod(s string) (OnsetMethod, error) {
+	for _, m := range AllOnsetMethods() {
+		if string(m) == normalizeMethod(s) {
+			return m, nil
+		}
+	}
+	return "", unknownMethodError("onset method", s, AllOnsetMethods())
+}
+
+func (m OnsetMethod) String() string {
+	return string(m)
+}
+
+func (m OnsetMethod) validate() error {
+	return validateMethod("onset method", m, AllOnsetMethods())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The empty OnsetMethod of a zero config is marshalled as OnsetDefault.
+func (m OnsetMethod) MarshalText() ([]byte, error) {
+	if m == "" {
+		return []byte(OnsetDefault), nil
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(m), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Empty text is unmarshalled as OnsetDefault.
+func (m *OnsetMethod) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = OnsetDefault
+		return nil
+	}
+	v, err := ParseOnsetMethod(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// PitchMethod names a pitch detection algorithm understood by
+// new_aubio_pitch.
+// See: https://github.com/aubio/aubio/blob/master/src/pitch/pitch.h
+type PitchMethod string
+
+const (
+	// Default pitch detection algorithm (currently yinfft)
+	PitchDefault PitchMethod = "default"
+	// YIN algorithm
+	PitchYin PitchMethod = "yin"
+	// Multi-comb filter
+	PitchMcomb PitchMethod = "mcomb"
+	// Schmitt trigger
+	PitchSchmitt PitchMethod = "schmitt"
+	// Fast harmonic comb filter
+	PitchFcomb PitchMethod = "fcomb"
+	// YIN computed in the spectral domain
+	PitchYinfft PitchMethod = "yinfft"
+	// YIN with the difference function computed using FFT convolution
+	PitchYinfast PitchMethod = "yinfast"
+	// Spectral auto-correlation
+	PitchSpecacf PitchMethod = "specacf"
+)
+
+// AllPitchMethods returns every supported PitchMethod.
+func AllPitchMethods() []PitchMethod {
+	return []PitchMethod{PitchDefault, PitchYin, PitchMcomb, PitchSchmitt,
+		PitchFcomb, PitchYinfft, PitchYinfast, PitchSpecacf}
+}
+
+// ParsePitchMethod returns the PitchMethod named by s.
+func ParsePitchMethod(s string) (PitchMethod, error) {
+	for _, m := range AllPitchMethods() {
+		if string(m) == normalizeMethod(s) {
+			return m, nil
+		}
+	}
+	return "", unknownMethodError("pitch method", s, AllPitchMethods())
+}
+
+func (m PitchMethod) String() string {
+	return string(m)
+}
+
+func (m PitchMethod) validate() error {
+	return validateMethod("pitch method", m, AllPitchMethods())
+}
+
+// reportsConfidence returns if aubio computes a confidence for m.
+// schmitt, fcomb and mcomb always report 0.
+func (m PitchMethod) reportsConfidence() bool {
+	switch m {
+	case PitchSchmitt, PitchFcomb, PitchMcomb:
+		return false
+	}
+	return true
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The empty PitchMethod of a zero config is marshalled as PitchDefault.
+func (m PitchMethod) MarshalText() ([]byte, error) {
+	if m == "" {
+		return []byte(PitchDefault), nil
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(m), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Empty text is unmarshalled as PitchDefault.
+func (m *PitchMethod) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = PitchDefault
+		return nil
+	}
+	v, err := ParsePitchMethod(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// PitchUnit names the unit Pitch reports its estimates in.
+type PitchUnit string
+
+const (
+	// Frequency in Hz
+	PitchOutFreq PitchUnit = "freq"
+	// Midi note number
+	PitchOutMidi PitchUnit = "midi"
+	// Cents
+	PitchOutCent PitchUnit = "cent"
+	// FFT bin
+	PitchOutBin PitchUnit = "bin"
+	// Default unit (currently freq)
+	PitchOutDefault PitchUnit = "default"
+)
+
+// AllPitchUnits returns every supported PitchUnit.
+func AllPitchUnits() []PitchUnit {
+	return []PitchUnit{PitchOutFreq, PitchOutMidi, PitchOutCent, PitchOutBin,
+		PitchOutDefault}
+}
+
+// ParsePitchUnit returns the PitchUnit named by s.
+func ParsePitchUnit(s string) (PitchUnit, error) {
+	for _, u := range AllPitchUnits() {
+		if string(u) == normalizeMethod(s) {
+			return u, nil
+		}
+	}
+	return "", unknownMethodError("pitch unit", s, AllPitchUnits())
+}
+
+func (u PitchUnit) String() string {
+	return string(u)
+}
+
+func (u PitchUnit) validate() error {
+	return validateMethod("pitch unit", u, AllPitchUnits())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The empty PitchUnit of a zero config is marshalled as PitchOutDefault.
+func (u PitchUnit) MarshalText() ([]byte, error) {
+	if u == "" {
+		return []byte(PitchOutDefault), nil
+	}
+	if err := u.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(u), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Empty text is unmarshalled as PitchOutDefault.
+func (u *PitchUnit) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*u = PitchOutDefault
+		return nil
+	}
+	v, err := ParsePitchUnit(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// SpecDescMethod names a spectral description function understood by
+// new_aubio_specdesc. It covers the onset detection functions as well
+// as the spectral shape descriptors.
+// See: https://github.com/aubio/aubio/blob/master/src/spectral/specdesc.h
+type SpecDescMethod string
+
+const (
+	// Onset detection functions, see the OnsetMethod constants.
+	SpecDescEnergy   SpecDescMethod = "energy"
+	SpecDescHFC      SpecDescMethod = "hfc"
+	SpecDescComplex  SpecDescMethod = "complex"
+	SpecDescPhase    SpecDescMethod = "phase"
+	SpecDescWPhase   SpecDescMethod = "wphase"
+	SpecDescSpecDiff SpecDescMethod = "specdiff"
+	SpecDescKL       SpecDescMethod = "kl"
+	SpecDescMKL      SpecDescMethod = "mkl"
+	SpecDescSpecFlux SpecDescMethod = "specflux"
+	// Default onset detection function (currently HFC)
+	SpecDescDefault SpecDescMethod = "default"
+
+	// Spectral centroid, in FFT bins
+	SpecDescCentroid SpecDescMethod = "centroid"
+	// Spectral spread
+	SpecDescSpread SpecDescMethod = "spread"
+	// Spectral skewness
+	SpecDescSkewness SpecDescMethod = "skewness"
+	// Spectral kurtosis
+	SpecDescKurtosis SpecDescMethod = "kurtosis"
+	// Spectral slope
+	SpecDescSlope SpecDescMethod = "slope"
+	// Spectral decrease
+	SpecDescDecrease SpecDescMethod = "decrease"
+	// Spectral roll-off, in FFT bins
+	SpecDescRolloff SpecDescMethod = "rolloff"
+)
+
+// AllSpecDescMethods returns every supported SpecDescMethod.
+func AllSpecDescMethods() []SpecDescMethod {
+	return []SpecDescMethod{SpecDescEnergy, SpecDescHFC, SpecDescComplex,
+		SpecDescPhase, SpecDescWPhase, SpecDescSpecDiff, SpecDescKL, SpecDescMKL,
+		SpecDescSpecFlux, SpecDescCentroid, SpecDescSpread, SpecDescSkewness,
+		SpecDescKurtosis, SpecDescSlope, SpecDescDecrease, SpecDescRolloff,
+		SpecDescDefault}
+}
+
+// ParseSpecDescMethod returns the SpecDescMethod named by s.
+func ParseSpecDescMethod(s string) (SpecDescMethod, error) {
+	for _, m := range AllSpecDescMethods() {
+		if string(m) == normalizeMethod(s) {
+			return m, nil
+		}
+	}
+	return "", unknownMethodError("specdesc method", s, AllSpecDescMethods())
+}
+
+func (m SpecDescMethod) String() string {
+	return string(m)
+}
+
+func (m SpecDescMethod) validate() error {
+	return validateMethod("specdesc method", m, AllSpecDescMethods())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The empty SpecDescMethod of a zero config is marshalled as SpecDescDefault.
+func (m SpecDescMethod) MarshalText() ([]byte, error) {
+	if m == "" {
+		return []byte(SpecDescDefault), nil
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(m), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Empty text is unmarshalled as SpecDescDefault.
+func (m *SpecDescMethod) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*m = SpecDescDefault
+		return nil
+	}
+	v, err := ParseSpecDescMethod(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// WindowType names a window function understood by new_aubio_window
+// and aubio_pvoc_set_window.
+// See: https://github.com/aubio/aubio/blob/master/src/mathutils.h
+type WindowType string
+
+const (
+	WindowOnes           WindowType = "ones"
+	WindowRectangle      WindowType = "rectangle"
+	WindowHamming        WindowType = "hamming"
+	WindowHanning        WindowType = "hanning"
+	WindowHanningz       WindowType = "hanningz"
+	WindowBlackman       WindowType = "blackman"
+	WindowBlackmanHarris WindowType = "blackman_harris"
+	WindowGaussian       WindowType = "gaussian"
+	WindowWelch          WindowType = "welch"
+	WindowParzen         WindowType = "parzen"
+	// Default window (currently hanningz)
+	WindowDefault WindowType = "default"
+)
+
+// AllWindowTypes returns every supported WindowType.
+func AllWindowTypes() []WindowType {
+	return []WindowType{WindowOnes, WindowRectangle, WindowHamming,
+		WindowHanning, WindowHanningz, WindowBlackman, WindowBlackmanHarris,
+		WindowGaussian, WindowWelch, WindowParzen, WindowDefault}
+}
+
+// ParseWindowType returns the WindowType named by s.
+func ParseWindowType(s string) (WindowType, error) {
+	for _, w := range AllWindowTypes() {
+		if string(w) == normalizeMethod(s) {
+			return w, nil
+		}
+	}
+	return "", unknownMethodError("window type", s, AllWindowTypes())
+}
+
+func (w WindowType) String() string {
+	return string(w)
+}
+
+func (w WindowType) validate() error {
+	return validateMethod("window type", w, AllWindowTypes())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The empty WindowType of a zero config is marshalled as WindowDefault.
+func (w WindowType) MarshalText() ([]byte, error) {
+	if w == "" {
+		return []byte(WindowDefault), nil
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(w), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// Empty text is unmarshalled as WindowDefault.
+func (w *WindowType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*w = WindowDefault
+		return nil
+	}
+	v, err := ParseWindowType(string(text))
+	if err != nil {
+		return err
+	}
+	*w = v
+	return nil
+}
+
+func normalizeMethod(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// validateMethod checks m is exactly one of all, without normalizing
+// it like the Parse functions do, as it is passed to aubio unchanged.
+func validateMethod[T interface {
+	~string
+	fmt.Stringer
+}](kind string, m T, all []T) error {
+	for _, a := range all {
+		if m == a {
+			return nil
+		}
+	}
+	return unknownMethodError(kind, string(m), all)
+}
+
+func unknownMethodError[T fmt.Stringer](kind, s string, all []T) error {
+	names := make([]string, len(all))
+	for i, m := range all {
+		names[i] = m.String()
+	}
+	return fmt.Errorf("unknown %s %q, expected one of: %s",
+		kind, s, strings.Join(names, ", "))
+}
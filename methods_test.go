@@ -0,0 +1,90 @@
+package aubio
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMethods(t *testing.T) {
+	for _, m := range AllOnsetMethods() {
+		if got, err := ParseOnsetMethod(m.String()); err != nil || got != m {
+			t.Errorf("ParseOnsetMethod(%q) = %q, %v", m, got, err)
+		}
+	}
+	for _, m := range AllSpecDescMethods() {
+		if got, err := ParseSpecDescMethod(m.String()); err != nil || got != m {
+			t.Errorf("ParseSpecDescMethod(%q) = %q, %v", m, got, err)
+		}
+	}
+	for _, w := range AllWindowTypes() {
+		if got, err := ParseWindowType(w.String()); err != nil || got != w {
+			t.Errorf("ParseWindowType(%q) = %q, %v", w, got, err)
+		}
+	}
+	if m, err := ParsePitchMethod(" YinFFT "); err != nil || m != PitchYinfft {
+		t.Errorf("ParsePitchMethod = %q, %v", m, err)
+	}
+	if _, err := ParsePitchUnit("furlongs"); err == nil {
+		t.Error("expected an error for an unknown pitch unit")
+	}
+	if _, err := NewOnset("k1", 512, 256, 44100); err == nil {
+		t.Error("expected NewOnset to reject an unknown method")
+	}
+	// names are passed to aubio as is, so only Parse normalizes them
+	if _, err := NewOnset(" HFC", 512, 256, 44100); err == nil {
+		t.Error("expected NewOnset to reject a mixed-case method")
+	}
+	if err := OnsetMethod("SpecFlux").validate(); err == nil {
+		t.Error("expected validate to reject a mixed-case method")
+	}
+	if err := WindowType("Hanning").validate(); err == nil {
+		t.Error("expected validate to reject a mixed-case window")
+	}
+}
+
+func TestMethodsJSON(t *testing.T) {
+	type config struct {
+		Onset OnsetMethod
+		Pitch PitchMethod
+		Unit  PitchUnit
+		Desc  SpecDescMethod
+	}
+	in := config{SpecFlux, PitchYin, PitchOutMidi, SpecDescCentroid}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out config
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("round trip got %+v, want %+v", out, in)
+	}
+	if err := json.Unmarshal([]byte(`{"Onset":"bogus"}`), &out); err == nil {
+		t.Error("expected an error unmarshalling an unknown onset method")
+	}
+	if _, err := json.Marshal(config{Onset: "bogus"}); err == nil {
+		t.Error("expected an error marshalling an unknown onset method")
+	}
+
+	// an unset method stands for the default one
+	b, err = json.Marshal(config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = config{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := (config{OnsetDefault, PitchDefault, PitchOutDefault, SpecDescDefault}); out != want {
+		t.Errorf("zero config round trip got %+v, want %+v", out, want)
+	}
+	out = config{}
+	if err := json.Unmarshal([]byte(`{"Onset":"","Pitch":""}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Onset != OnsetDefault || out.Pitch != PitchDefault {
+		t.Errorf("empty methods got %+v, want the defaults", out)
+	}
+}
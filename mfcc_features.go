@@ -0,0 +1,169 @@
+package aubio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MFCCConfig configures ExtractMFCC. Zero fields take their default.
+type MFCCConfig struct {
+	// BufSize is the analysis window in samples.
+	// Defaults to 4 times the block size of the Source.
+	BufSize uint
+	// Filters is the number of Mel bands. Defaults to 40.
+	Filters uint
+	// Coeffs is the number of coefficients per frame. Defaults to 13.
+	Coeffs uint
+	// Deltas appends the first order deltas of the coefficients to
+	// each frame.
+	Deltas bool
+	// DeltaDeltas appends the second order deltas of the coefficients
+	// to each frame, after the first order ones if any.
+	DeltaDeltas bool
+	// DeltaWidth is the number of frames either side the deltas are
+	// regressed over. Defaults to 2.
+	DeltaWidth int
+	// CMVN normalizes each feature to zero mean and unit variance over
+	// the whole Source.
+	CMVN bool
+}
+
+// ExtractMFCC computes the MFCCs of every block of src, one frame per
+// block. Each frame holds the coefficients, then the deltas and
+// delta-deltas when enabled in cfg.
+func ExtractMFCC(src *Source, cfg MFCCConfig) ([][]float64, error) {
+	hop := src.BlockSize()
+	if cfg.BufSize == 0 {
+		cfg.BufSize = 4 * hop
+	}
+	if cfg.Filters == 0 {
+		cfg.Filters = 40
+	}
+	if cfg.Coeffs == 0 {
+		cfg.Coeffs = 13
+	}
+	if cfg.DeltaWidth == 0 {
+		cfg.DeltaWidth = 2
+	}
+	pv, err := NewPhaseVoc(cfg.BufSize, hop)
+	if err != nil {
+		return nil, err
+	}
+	defer pv.Free()
+	mfcc, err := NewMFCC(cfg.BufSize, src.Samplerate(), cfg.Coeffs, cfg.Filters)
+	if err != nil {
+		return nil, err
+	}
+	defer mfcc.Free()
+	buf := NewSimpleBuffer(hop)
+	defer buf.Free()
+	var frames [][]float64
+	for {
+		n := src.Do(buf)
+		if n == 0 {
+			break
+		}
+		pv.Do(buf)
+		mfcc.Do(pv.Grain())
+		frames = append(frames, mfcc.Coeffs().Slice())
+		if n < hop {
+			break
+		}
+	}
+	if len(frames) == 0 {
+		return nil, errors.New("no frames read from source")
+	}
+
+	features := frames
+	if cfg.Deltas || cfg.DeltaDeltas {
+		deltas := Deltas(frames, cfg.DeltaWidth)
+		if cfg.Deltas {
+			features = appendFeatures(features, deltas)
+		}
+		if cfg.DeltaDeltas {
+			features = appendFeatures(features, Deltas(deltas, cfg.DeltaWidth))
+		}
+	}
+	if cfg.CMVN {
+		CMVN(features)
+	}
+	return features, nil
+}
+
+// Deltas returns the regression of each feature over the width frames
+// either side, as HTK computes its delta coefficients. The first and
+// last frames are repeated past the edges.
+func Deltas(frames [][]float64, width int) [][]float64 {
+	var norm float64
+	for n := 1; n <= width; n++ {
+		norm += float64(2 * n * n)
+	}
+	deltas := make([][]float64, len(frames))
+	for t := range frames {
+		deltas[t] = make([]float64, len(frames[t]))
+		if norm == 0 {
+			continue
+		}
+		for n := 1; n <= width; n++ {
+			next := frames[minInt(t+n, len(frames)-1)]
+			prev := frames[maxInt(t-n, 0)]
+			for i := range deltas[t] {
+				deltas[t][i] += float64(n) * (next[i] - prev[i]) / norm
+			}
+		}
+	}
+	return deltas
+}
+
+// CMVN normalizes each feature of frames in place to zero mean and,
+// unless it is constant, unit variance.
+func CMVN(frames [][]float64) {
+	if len(frames) == 0 {
+		return
+	}
+	for i := range frames[0] {
+		var mean, variance float64
+		for _, f := range frames {
+			mean += f[i]
+		}
+		mean /= float64(len(frames))
+		for _, f := range frames {
+			variance += (f[i] - mean) * (f[i] - mean)
+		}
+		std := math.Sqrt(variance / float64(len(frames)))
+		for _, f := range frames {
+			f[i] -= mean
+			if std > 0 {
+				f[i] /= std
+			}
+		}
+	}
+}
+
+// FeatureMatrix copies frames into a new MatrixBuffer with one channel
+// per frame. The frames must all have the same, non zero, length.
+// It is the Callers responsibility to call Free on the returned
+// MatrixBuffer or leak memory.
+func FeatureMatrix(frames [][]float64) (*MatrixBuffer, error) {
+	if len(frames) == 0 || len(frames[0]) == 0 {
+		return nil, errors.New("no features to copy into a matrix")
+	}
+	for i, f := range frames {
+		if len(f) != len(frames[0]) {
+			return nil, fmt.Errorf("frame %d has %d features, want %d", i, len(f), len(frames[0]))
+		}
+	}
+	mb := NewMatrixBuffer(uint(len(frames)), uint(len(frames[0])))
+	mb.SetChannels(frames)
+	return mb, nil
+}
+
+// appendFeatures returns the frames of a with those of b appended.
+func appendFeatures(a, b [][]float64) [][]float64 {
+	out := make([][]float64, len(a))
+	for t := range a {
+		out[t] = append(append(make([]float64, 0, len(a[t])+len(b[t])), a[t]...), b[t]...)
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMFCCLifecycle(t *testing.T) {
+	mfcc, err := NewMFCC(testBufSize, testSamplerate, 13, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := mfcc.Coeffs().Size(); n != 13 {
+		t.Errorf("got %d coefficients, want 13", n)
+	}
+	grain := NewComplexBuffer(testBufSize)
+	defer grain.Free()
+	mfcc.Free()
+	// must not crash once freed
+	mfcc.Do(grain)
+	mfcc.SetPower(2)
+	if mfcc.GetPower() != 0 {
+		t.Error("expected 0 power once freed")
+	}
+}
+
+func TestDeltas(t *testing.T) {
+	// a feature rising by 0.5 per frame has a delta of 0.5 away from
+	// the edges, and a delta-delta of 0
+	frames := make([][]float64, 10)
+	for i := range frames {
+		frames[i] = []float64{0.5 * float64(i), 3}
+	}
+	deltas := Deltas(frames, 2)
+	for i := 2; i < len(frames)-2; i++ {
+		if math.Abs(deltas[i][0]-0.5) > 1e-9 || deltas[i][1] != 0 {
+			t.Errorf("frame %d: deltas %v, want [0.5 0]", i, deltas[i])
+		}
+	}
+	if deltas[0][0] >= 0.5 || deltas[0][0] <= 0 {
+		t.Errorf("edge delta %v, want between 0 and 0.5", deltas[0][0])
+	}
+	for i, d := range Deltas(deltas, 2)[4:6] {
+		if math.Abs(d[0]) > 1e-9 {
+			t.Errorf("frame %d: delta-delta %v, want 0", i+4, d[0])
+		}
+	}
+}
+
+func TestCMVN(t *testing.T) {
+	frames := [][]float64{{1, 5}, {2, 5}, {3, 5}, {6, 5}}
+	CMVN(frames)
+	var mean, variance float64
+	for _, f := range frames {
+		mean += f[0]
+		variance += f[0] * f[0]
+		if f[1] != 0 {
+			t.Errorf("constant feature normalized to %v, want 0", f[1])
+		}
+	}
+	if math.Abs(mean) > 1e-9 || math.Abs(variance/4-1) > 1e-9 {
+		t.Errorf("mean %v variance %v, want 0 and 1", mean/4, variance/4)
+	}
+}
+
+func TestFeatureMatrix(t *testing.T) {
+	frames := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	mb, err := FeatureMatrix(frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mb.Free()
+	if mb.Height != 2 || mb.Length != 3 {
+		t.Fatalf("got %dx%d matrix, want 2x3", mb.Height, mb.Length)
+	}
+	if got := mb.GetChannel(1); got[2] != 6 {
+		t.Errorf("channel 1 = %v, want %v", got, frames[1])
+	}
+	if _, err := FeatureMatrix(nil); err == nil {
+		t.Error("expected an error without frames")
+	}
+	if _, err := FeatureMatrix([][]float64{{1, 2}, {3}}); err == nil {
+		t.Error("expected an error for ragged frames")
+	}
+}
+
+func TestExtractMFCC(t *testing.T) {
+	path := t.TempDir() + "/tone.wav"
+	sink, err := OpenSink(path, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signal := tone(440, testSamplerate, testSamplerate/2)
+	buf := NewSimpleBuffer(testHopSize)
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		buf.SetData(signal[i : i+testHopSize])
+		sink.Do(buf, testHopSize)
+	}
+	buf.Free()
+	sink.Close()
+
+	src, err := OpenSource(path, testSamplerate, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	frames, err := ExtractMFCC(src, MFCCConfig{Deltas: true, DeltaDeltas: true, CMVN: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(signal) / testHopSize; len(frames) < want-1 {
+		t.Errorf("got %d frames, want %d", len(frames), want)
+	}
+	if len(frames[0]) != 39 {
+		t.Errorf("got %d features per frame, want 39", len(frames[0]))
+	}
+}
@@ -0,0 +1,113 @@
+// Package midi writes and reads Standard MIDI Files.
+//
+// It is meant for exporting the notes, onsets, beats and tempo
+// detected by aubio into DAWs and sequencers. Times are given in
+// seconds and converted to ticks using the tempo map of the Sequence.
+package midi
+
+// Format is the Standard MIDI File format.
+type Format uint16
+
+const (
+	// SingleTrack (format 0) merges every event into one track.
+	SingleTrack Format = 0
+	// MultiTrack (format 1) writes a tempo track followed by a note
+	// track and a percussion track.
+	MultiTrack Format = 1
+)
+
+const (
+	// DefaultDivision is the default number of ticks per quarter note.
+	DefaultDivision = 480
+	// DefaultBPM is the tempo used before the first tempo change.
+	DefaultBPM = 120.
+	// PercussionChannel is the General MIDI percussion channel (10),
+	// numbered from zero.
+	PercussionChannel = 9
+)
+
+// General MIDI percussion keys.
+const (
+	KeyBassDrum      = 36
+	KeySideStick     = 37
+	KeySnare         = 38
+	KeyClosedHiHat   = 42
+	KeyHighWoodBlock = 76
+)
+
+const (
+	statusNoteOff = 0x80
+	statusNoteOn  = 0x90
+	statusMeta    = 0xff
+	metaTrackName = 0x03
+	metaEndTrack  = 0x2f
+	metaTempo     = 0x51
+	// maxChannel and maxData are the largest channel and data byte,
+	// such as a key or a velocity, a channel voice message can hold.
+	maxChannel = 15
+	maxData    = 127
+)
+
+// Event is a single MIDI event.
+type Event struct {
+	// Tick is the absolute time of the event in ticks.
+	Tick uint32
+	// Status is the status byte: 0xFF for meta events, 0xF0 or 0xF7
+	// for sysex, otherwise a channel voice message.
+	Status byte
+	// Meta is the meta event type when Status is 0xFF.
+	Meta byte
+	Data []byte
+}
+
+// Channel returns the channel of a channel voice event.
+func (e Event) Channel() uint8 {
+	return e.Status & 0x0f
+}
+
+// IsNoteOn returns if e is a note-on with a non zero velocity.
+func (e Event) IsNoteOn() bool {
+	return e.Status&0xf0 == statusNoteOn && len(e.Data) == 2 && e.Data[1] > 0
+}
+
+// IsNoteOff returns if e is a note-off, or a note-on with a zero velocity.
+func (e Event) IsNoteOff() bool {
+	kind := e.Status & 0xf0
+	return len(e.Data) == 2 &&
+		(kind == statusNoteOff || kind == statusNoteOn && e.Data[1] == 0)
+}
+
+// Tempo returns the tempo in BPM of a tempo meta event.
+func (e Event) Tempo() (float64, bool) {
+	if e.Status != statusMeta || e.Meta != metaTempo || len(e.Data) != 3 {
+		return 0, false
+	}
+	usPerQuarter := uint32(e.Data[0])<<16 | uint32(e.Data[1])<<8 | uint32(e.Data[2])
+	return 60e6 / float64(usPerQuarter), true
+}
+
+// Track is a list of events ordered by tick.
+type Track struct {
+	Events []Event
+}
+
+// File is a parsed Standard MIDI File.
+type File struct {
+	Format   Format
+	Division uint16
+	Tracks   []Track
+}
+
+// Seconds converts a tick to seconds using the tempo events found
+// in f.
+func (f *File) Seconds(tick uint32) float64 {
+	var tempos []tempoChange
+	for _, tr := range f.Tracks {
+		for _, e := range tr.Events {
+			if bpm, ok := e.Tempo(); ok {
+				tempos = append(tempos, tempoChange{tick: e.Tick, bpm: bpm})
+			}
+		}
+	}
+	return ticksToSeconds(tempos, tick, f.Division)
+}
@@ -0,0 +1,263 @@
+package midi
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func roundTrip(t *testing.T, s *Sequence, format Format) *File {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s.Write(&buf, format); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Format != format {
+		t.Errorf("format = %d, want %d", f.Format, format)
+	}
+	if f.Division != s.Division {
+		t.Errorf("division = %d, want %d", f.Division, s.Division)
+	}
+	return f
+}
+
+type note struct {
+	start, end float64
+	key, vel   uint8
+	channel    uint8
+}
+
+func collectNotes(f *File) []note {
+	var notes []note
+	open := map[[2]uint8]int{}
+	for _, tr := range f.Tracks {
+		for _, e := range tr.Events {
+			if len(e.Data) != 2 {
+				continue
+			}
+			v := [2]uint8{e.Channel(), e.Data[0]}
+			switch {
+			case e.IsNoteOn():
+				open[v] = len(notes)
+				notes = append(notes, note{start: f.Seconds(e.Tick), key: e.Data[0], vel: e.Data[1], channel: e.Channel()})
+			case e.IsNoteOff():
+				notes[open[v]].end = f.Seconds(e.Tick)
+			}
+		}
+	}
+	return notes
+}
+
+func near(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{SingleTrack, MultiTrack} {
+		s := NewSequence()
+		s.SetTempo(0, 90)
+		s.Note(0.5, 0.25, 60, 90)
+		s.Note(1.0, 0.5, 64, 70)
+		s.AddOnsets([]float64{0.5, 1.0})
+		f := roundTrip(t, s, format)
+
+		wantTracks := 1
+		if format == MultiTrack {
+			wantTracks = 3
+		}
+		if len(f.Tracks) != wantTracks {
+			t.Fatalf("format %d: %d tracks, want %d", format, len(f.Tracks), wantTracks)
+		}
+		want := []note{
+			{0.5, 0.75, 60, 90, 0},
+			{1.0, 1.5, 64, 70, 0},
+			{0.5, 0.5 + HitDuration, KeyBassDrum, 100, PercussionChannel},
+			{1.0, 1.0 + HitDuration, KeyBassDrum, 100, PercussionChannel},
+		}
+		got := collectNotes(f)
+		if len(got) != len(want) {
+			t.Fatalf("format %d: got %d notes, want %d", format, len(got), len(want))
+		}
+		for _, w := range want {
+			found := false
+			for _, g := range got {
+				if g.key == w.key && g.vel == w.vel && g.channel == w.channel &&
+					near(g.start, w.start, 1e-3) && near(g.end, w.end, 1e-3) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("format %d: note %+v not found in %+v", format, w, got)
+			}
+		}
+	}
+}
+
+func TestTempoChanges(t *testing.T) {
+	s := NewSequence()
+	s.SetTempo(0, 120)
+	s.SetTempo(2, 60)
+	s.SetTempo(4, 150)
+	for _, beat := range []float64{0, 1, 2, 3, 4, 5, 6} {
+		s.Hit(beat, KeySnare, 100)
+	}
+	f := roundTrip(t, s, MultiTrack)
+
+	var bpms []float64
+	for _, e := range f.Tracks[0].Events {
+		if bpm, ok := e.Tempo(); ok {
+			bpms = append(bpms, bpm)
+		}
+	}
+	if len(bpms) != 3 || !near(bpms[0], 120, 1e-3) || !near(bpms[1], 60, 1e-3) || !near(bpms[2], 150, 1e-3) {
+		t.Errorf("tempos = %v, want [120 60 150]", bpms)
+	}
+	i := 0
+	for _, e := range f.Tracks[1].Events {
+		if !e.IsNoteOn() {
+			continue
+		}
+		if got := f.Seconds(e.Tick); !near(got, float64(i), 1e-3) {
+			t.Errorf("hit %d at %vs, want %vs", i, got, i)
+		}
+		i++
+	}
+	if i != 7 {
+		t.Errorf("got %d hits, want 7", i)
+	}
+}
+
+func TestRepeatedNote(t *testing.T) {
+	s := NewSequence()
+	s.Note(0, 0.5, 60, 100)
+	s.Note(0.5, 0.5, 60, 100)
+	// left sounding, closed at the end of the track
+	s.NoteOn(1.5, 62, 100)
+	f := roundTrip(t, s, SingleTrack)
+	got := collectNotes(f)
+	if len(got) != 3 {
+		t.Fatalf("got %d notes, want 3", len(got))
+	}
+	if !near(got[0].end, 0.5, 1e-3) || !near(got[1].end, 1, 1e-3) || !near(got[2].end, 1.625, 1e-3) {
+		t.Errorf("notes = %+v", got)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	s := NewSequence()
+	for _, bpm := range []float64{0, -60, 3.5, math.Inf(1), math.NaN()} {
+		if err := s.SetTempo(0, bpm); err == nil {
+			t.Errorf("expected an error for a tempo of %v BPM", bpm)
+		}
+	}
+	if err := s.SetTempo(0, 3.6); err != nil {
+		t.Errorf("3.6 BPM: %v", err)
+	}
+	// tempos added directly are checked again on Write
+	s.tempos = append(s.tempos, tempoChange{time: 1, bpm: 1})
+	if err := s.Write(&bytes.Buffer{}, SingleTrack); err == nil {
+		t.Error("expected an error writing a tempo of 1 BPM")
+	}
+	if err := NewSequence().Write(&bytes.Buffer{}, Format(2)); err == nil {
+		t.Error("expected an error for format 2")
+	}
+	if _, err := Read(bytes.NewReader([]byte("RIFF0000"))); err == nil {
+		t.Error("expected an error reading a non MIDI file")
+	}
+	if _, err := Read(bytes.NewReader([]byte("MThd\x00\x00\x00\x06\x00\x00\x00\x00\x00\x00"))); err == nil {
+		t.Error("expected an error reading a division of 0")
+	}
+}
+
+func TestInvalidNotes(t *testing.T) {
+	s := NewSequence()
+	if err := s.NoteOn(0, 128, 100); err == nil {
+		t.Error("expected an error for key 128")
+	}
+	if err := s.Note(0, 1, 60, 200); err == nil {
+		t.Error("expected an error for velocity 200")
+	}
+	if err := s.Hit(0, 255, 100); err == nil {
+		t.Error("expected an error for percussion key 255")
+	}
+	s.OnsetKey = 128
+	if err := s.AddOnsets([]float64{0}); err == nil {
+		t.Error("expected an error for onset key 128")
+	}
+	s.NoteChannel = 16
+	if err := s.NoteOn(0, 60, 100); err == nil {
+		t.Error("expected an error for channel 16")
+	}
+	if err := s.Write(&bytes.Buffer{}, SingleTrack); err == nil {
+		t.Error("expected Write to reject channel 16")
+	}
+}
+
+func TestCorruptLengths(t *testing.T) {
+	header := []byte("MThd\x00\x00\x00\x06\x00\x00\x00\x01\x01\xe0")
+	// a track claiming to be 4GB long
+	huge := append(append([]byte(nil), header...), "MTrk\xff\xff\xff\xff\x00\xff\x2f\x00"...)
+	if _, err := Read(bytes.NewReader(huge)); err == nil {
+		t.Error("expected an error for a truncated track")
+	}
+	// a meta event claiming to hold 256MB
+	track := []byte("\x00\xff\x01\xff\xff\xff\x7fabc")
+	meta := append(append([]byte(nil), header...), "MTrk\x00\x00\x00"...)
+	meta = append(append(meta, byte(len(track))), track...)
+	if _, err := Read(bytes.NewReader(meta)); err == nil {
+		t.Error("expected an error for a truncated meta event")
+	}
+}
+
+func TestVarLen(t *testing.T) {
+	for _, tc := range []struct {
+		v    uint32
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0x81, 0x00}},
+		{0x2000, []byte{0xc0, 0x00}},
+		{0x3fff, []byte{0xff, 0x7f}},
+		{0x4000, []byte{0x81, 0x80, 0x00}},
+		{0x0fffffff, []byte{0xff, 0xff, 0xff, 0x7f}},
+	} {
+		got := appendVarLen(nil, tc.v)
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("appendVarLen(%#x) = % x, want % x", tc.v, got, tc.want)
+		}
+		v, err := readVarLen(bytes.NewReader(got))
+		if err != nil || v != tc.v {
+			t.Errorf("readVarLen(% x) = %#x, %v, want %#x", got, v, err, tc.v)
+		}
+	}
+	if _, err := readVarLen(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x7f})); err == nil {
+		t.Error("expected an error for a five byte quantity")
+	}
+}
+
+func TestRunningStatus(t *testing.T) {
+	track := []byte{
+		0x00, 0x90, 60, 100,
+		0x60, 62, 100, // running status note-on
+		0x60, 60, 0, // note-on with zero velocity
+		0x00, 0xff, 0x2f, 0x00,
+	}
+	tr, err := parseTrack(track)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Events) != 3 {
+		t.Fatalf("got %d events, want 3", len(tr.Events))
+	}
+	if !tr.Events[1].IsNoteOn() || tr.Events[1].Tick != 0x60 {
+		t.Errorf("event 1 = %+v", tr.Events[1])
+	}
+	if !tr.Events[2].IsNoteOff() || tr.Events[2].Tick != 0xc0 {
+		t.Errorf("event 2 = %+v", tr.Events[2])
+	}
+}
@@ -0,0 +1,160 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var errVarLen = errors.New("invalid variable length quantity")
+
+// Read parses a Standard MIDI File.
+func Read(r io.Reader) (*File, error) {
+	var header [14]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("reading MIDI header: %w", err)
+	}
+	if string(header[:4]) != "MThd" || binary.BigEndian.Uint32(header[4:]) < 6 {
+		return nil, errors.New("not a Standard MIDI File")
+	}
+	if extra := binary.BigEndian.Uint32(header[4:]) - 6; extra > 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(extra)); err != nil {
+			return nil, err
+		}
+	}
+	f := &File{
+		Format:   Format(binary.BigEndian.Uint16(header[8:])),
+		Division: binary.BigEndian.Uint16(header[12:]),
+	}
+	if f.Division&0x8000 != 0 {
+		return nil, errors.New("SMPTE time division is not supported")
+	}
+	if f.Division == 0 {
+		return nil, errors.New("invalid division 0")
+	}
+	n := int(binary.BigEndian.Uint16(header[10:]))
+	for len(f.Tracks) < n {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, fmt.Errorf("reading track %d: %w", len(f.Tracks), err)
+		}
+		size := int64(binary.BigEndian.Uint32(chunk[4:]))
+		if string(chunk[:4]) != "MTrk" {
+			// skip unknown chunks
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("reading track %d: %w", len(f.Tracks), err)
+			}
+			continue
+		}
+		// copy rather than allocate size bytes up front, as a corrupt
+		// length could ask for up to 4GB
+		var data bytes.Buffer
+		if _, err := io.CopyN(&data, r, size); err != nil {
+			return nil, fmt.Errorf("reading track %d: %w", len(f.Tracks), err)
+		}
+		tr, err := parseTrack(data.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", len(f.Tracks), err)
+		}
+		f.Tracks = append(f.Tracks, tr)
+	}
+	return f, nil
+}
+
+// ReadFile parses the Standard MIDI File at path.
+func ReadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+func parseTrack(data []byte) (Track, error) {
+	var tr Track
+	r := bytes.NewReader(data)
+	var tick uint32
+	var running byte
+	for r.Len() > 0 {
+		delta, err := readVarLen(r)
+		if err != nil {
+			return tr, err
+		}
+		tick += delta
+		status, err := r.ReadByte()
+		if err != nil {
+			return tr, err
+		}
+		e := Event{Tick: tick}
+		switch {
+		case status == statusMeta:
+			e.Status = status
+			if e.Meta, err = r.ReadByte(); err != nil {
+				return tr, err
+			}
+			if e.Data, err = readData(r); err != nil {
+				return tr, err
+			}
+			if e.Meta == metaEndTrack {
+				return tr, nil
+			}
+		case status == 0xf0 || status == 0xf7:
+			e.Status = status
+			if e.Data, err = readData(r); err != nil {
+				return tr, err
+			}
+		default:
+			if status < 0x80 {
+				if running == 0 {
+					return tr, errors.New("data byte without running status")
+				}
+				r.UnreadByte()
+				status = running
+			}
+			running = status
+			e.Status = status
+			size := 2
+			if kind := status & 0xf0; kind == 0xc0 || kind == 0xd0 {
+				size = 1
+			}
+			e.Data = make([]byte, size)
+			if _, err := io.ReadFull(r, e.Data); err != nil {
+				return tr, err
+			}
+		}
+		tr.Events = append(tr.Events, e)
+	}
+	return tr, errors.New("missing end of track")
+}
+
+func readData(r *bytes.Reader) ([]byte, error) {
+	n, err := readVarLen(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(n) > int64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, n)
+	_, err = io.ReadFull(r, data)
+	return data, err
+}
+
+func readVarLen(r io.ByteReader) (uint32, error) {
+	var v uint32
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<7 | uint32(b&0x7f)
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errVarLen
+}
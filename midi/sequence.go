@@ -0,0 +1,324 @@
+package midi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// HitDuration is how long, in seconds, the percussion notes written
+// for onsets and beats last.
+const HitDuration = 0.05
+
+// maxTempo is the largest quarter note duration in microseconds that
+// fits the 24 bits of a tempo meta event, about 3.58 BPM.
+const maxTempo = 0xffffff
+
+// timedEvent is a channel voice event at a time in seconds.
+type timedEvent struct {
+	time   float64
+	status byte
+	data   [2]byte
+}
+
+// Sequence collects notes, percussion hits and tempo changes, timed in
+// seconds, to write them out as a Standard MIDI File.
+type Sequence struct {
+	// Division is the number of ticks per quarter note.
+	Division uint16
+	// NoteChannel is the channel notes are written on, from zero.
+	NoteChannel uint8
+	// OnsetKey and BeatKey are the percussion keys used by AddOnsets
+	// and AddBeats.
+	OnsetKey uint8
+	BeatKey  uint8
+
+	tempos []tempoChange
+	notes  []timedEvent
+	hits   []timedEvent
+}
+
+// NewSequence constructs an empty Sequence with the default division
+// and percussion keys.
+func NewSequence() *Sequence {
+	return &Sequence{
+		Division: DefaultDivision,
+		OnsetKey: KeyBassDrum,
+		BeatKey:  KeyHighWoodBlock,
+	}
+}
+
+// SetTempo adds a tempo change at t seconds, for instance the value
+// of Tempo.GetBpm after each beat. bpm must be positive.
+func (s *Sequence) SetTempo(t, bpm float64) error {
+	if err := checkTempo(t, bpm); err != nil {
+		return err
+	}
+	s.tempos = append(s.tempos, tempoChange{time: t, bpm: bpm})
+	return nil
+}
+
+// checkTempo returns an error if bpm cannot be written as a tempo meta
+// event.
+func checkTempo(t, bpm float64) error {
+	if bpm <= 0 || math.IsNaN(bpm) || math.IsInf(bpm, 0) {
+		return fmt.Errorf("invalid tempo %v BPM at %vs", bpm, t)
+	}
+	if us := math.Round(60e6 / bpm); us < 1 || us > maxTempo {
+		return fmt.Errorf("tempo %v BPM at %vs is out of the MIDI range", bpm, t)
+	}
+	return nil
+}
+
+// NoteOn starts a note at t seconds. key and velocity must be at
+// most 127.
+func (s *Sequence) NoteOn(t float64, key, velocity uint8) error {
+	if velocity == 0 {
+		return s.NoteOff(t, key)
+	}
+	if err := s.checkNote(key, velocity); err != nil {
+		return err
+	}
+	s.notes = append(s.notes, timedEvent{t, statusNoteOn | s.NoteChannel, [2]byte{key, velocity}})
+	return nil
+}
+
+// NoteOff ends a note at t seconds.
+func (s *Sequence) NoteOff(t float64, key uint8) error {
+	if err := s.checkNote(key, 0); err != nil {
+		return err
+	}
+	s.notes = append(s.notes, timedEvent{t, statusNoteOff | s.NoteChannel, [2]byte{key, 0}})
+	return nil
+}
+
+// Note adds a note starting at start seconds and lasting duration seconds.
+func (s *Sequence) Note(start, duration float64, key, velocity uint8) error {
+	if err := s.NoteOn(start, key, velocity); err != nil {
+		return err
+	}
+	return s.NoteOff(start+duration, key)
+}
+
+// checkNote returns an error when key, velocity or NoteChannel do not
+// fit in a channel voice message.
+func (s *Sequence) checkNote(key, velocity uint8) error {
+	if s.NoteChannel > maxChannel {
+		return fmt.Errorf("invalid channel %d, must be at most %d", s.NoteChannel, maxChannel)
+	}
+	return checkKey(key, velocity)
+}
+
+// Hit adds a percussion hit at t seconds. key and velocity must be at
+// most 127.
+func (s *Sequence) Hit(t float64, key, velocity uint8) error {
+	if err := checkKey(key, velocity); err != nil {
+		return err
+	}
+	s.hits = append(s.hits,
+		timedEvent{t, statusNoteOn | PercussionChannel, [2]byte{key, velocity}},
+		timedEvent{t + HitDuration, statusNoteOff | PercussionChannel, [2]byte{key, 0}})
+	return nil
+}
+
+// AddOnsets adds a percussion hit on OnsetKey for each onset time.
+func (s *Sequence) AddOnsets(times []float64) error {
+	for _, t := range times {
+		if err := s.Hit(t, s.OnsetKey, 100); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddBeats adds a percussion hit on BeatKey for each beat time.
+func (s *Sequence) AddBeats(times []float64) error {
+	for _, t := range times {
+		if err := s.Hit(t, s.BeatKey, 100); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkKey(key, velocity uint8) error {
+	if key > maxData {
+		return fmt.Errorf("invalid key %d, must be at most %d", key, maxData)
+	}
+	if velocity > maxData {
+		return fmt.Errorf("invalid velocity %d, must be at most %d", velocity, maxData)
+	}
+	return nil
+}
+
+// WriteFile writes the Sequence to a Standard MIDI File at path.
+func (s *Sequence) WriteFile(path string, format Format) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes the Sequence as a Standard MIDI File of the given format.
+// Notes still sounding at the end of the Sequence are ended a
+// sixteenth note after its last event.
+func (s *Sequence) Write(w io.Writer, format Format) error {
+	if s.Division == 0 || s.Division > 0x7fff {
+		return fmt.Errorf("invalid division %d", s.Division)
+	}
+	if s.NoteChannel > maxChannel {
+		return fmt.Errorf("invalid channel %d, must be at most %d", s.NoteChannel, maxChannel)
+	}
+	for _, tc := range s.tempos {
+		if err := checkTempo(tc.time, tc.bpm); err != nil {
+			return err
+		}
+	}
+	tempos := resolveTempos(s.tempos, s.Division)
+	tempoTrack := []Event{{Status: statusMeta, Meta: metaTrackName, Data: []byte("Tempo")}}
+	for _, tc := range tempos {
+		us := uint32(math.Round(60e6 / tc.bpm))
+		tempoTrack = append(tempoTrack, Event{Tick: tc.tick, Status: statusMeta, Meta: metaTempo,
+			Data: []byte{byte(us >> 16), byte(us >> 8), byte(us)}})
+	}
+	notes := s.channelEvents(tempos, s.notes)
+	hits := s.channelEvents(tempos, s.hits)
+
+	var tracks [][]Event
+	switch format {
+	case SingleTrack:
+		all := append(append(tempoTrack, notes...), hits...)
+		sortEvents(all)
+		tracks = [][]Event{all}
+	case MultiTrack:
+		tracks = [][]Event{tempoTrack}
+		if len(notes) > 0 {
+			name := Event{Status: statusMeta, Meta: metaTrackName, Data: []byte("Notes")}
+			tracks = append(tracks, append([]Event{name}, notes...))
+		}
+		if len(hits) > 0 {
+			name := Event{Status: statusMeta, Meta: metaTrackName, Data: []byte("Percussion")}
+			tracks = append(tracks, append([]Event{name}, hits...))
+		}
+	default:
+		return fmt.Errorf("unsupported MIDI file format %d", format)
+	}
+
+	bw := bufio.NewWriter(w)
+	header := make([]byte, 14)
+	copy(header, "MThd")
+	binary.BigEndian.PutUint32(header[4:], 6)
+	binary.BigEndian.PutUint16(header[8:], uint16(format))
+	binary.BigEndian.PutUint16(header[10:], uint16(len(tracks)))
+	binary.BigEndian.PutUint16(header[12:], s.Division)
+	bw.Write(header)
+	for _, tr := range tracks {
+		writeTrack(bw, tr)
+	}
+	return bw.Flush()
+}
+
+// channelEvents converts timed events to ticks, sorts them and closes
+// any notes left sounding.
+func (s *Sequence) channelEvents(tempos []tempoChange, timed []timedEvent) []Event {
+	events := make([]Event, 0, len(timed))
+	for _, te := range timed {
+		events = append(events, Event{
+			Tick:   secondsToTicks(tempos, te.time, s.Division),
+			Status: te.status,
+			Data:   []byte{te.data[0], te.data[1]},
+		})
+	}
+	sortEvents(events)
+	type voice struct{ status, key byte }
+	sounding := map[voice]int{}
+	var last uint32
+	for _, e := range events {
+		v := voice{e.Status & 0x0f, e.Data[0]}
+		if e.IsNoteOn() {
+			sounding[v]++
+		} else if sounding[v] > 0 {
+			sounding[v]--
+		}
+		last = e.Tick
+	}
+	var open []voice
+	for v, n := range sounding {
+		for i := 0; i < n; i++ {
+			open = append(open, v)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].status < open[j].status ||
+			open[i].status == open[j].status && open[i].key < open[j].key
+	})
+	end := last + uint32(s.Division)/4
+	for _, v := range open {
+		events = append(events, Event{Tick: end, Status: statusNoteOff | v.status, Data: []byte{v.key, 0}})
+	}
+	return events
+}
+
+// sortEvents orders events by tick, putting meta events first and
+// note-offs before note-ons at the same tick so repeated notes are
+// not cut short.
+func sortEvents(events []Event) {
+	rank := func(e Event) int {
+		switch {
+		case e.Status == statusMeta:
+			return 0
+		case e.IsNoteOff():
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Tick != events[j].Tick {
+			return events[i].Tick < events[j].Tick
+		}
+		return rank(events[i]) < rank(events[j])
+	})
+}
+
+func writeTrack(w io.Writer, events []Event) {
+	var data []byte
+	var last uint32
+	for _, e := range events {
+		data = appendVarLen(data, e.Tick-last)
+		last = e.Tick
+		data = append(data, e.Status)
+		if e.Status == statusMeta {
+			data = append(data, e.Meta)
+			data = appendVarLen(data, uint32(len(e.Data)))
+		}
+		data = append(data, e.Data...)
+	}
+	data = append(data, 0, statusMeta, metaEndTrack, 0)
+	header := make([]byte, 8)
+	copy(header, "MTrk")
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	w.Write(header)
+	w.Write(data)
+}
+
+// appendVarLen appends v as a MIDI variable length quantity.
+func appendVarLen(b []byte, v uint32) []byte {
+	var tmp [5]byte
+	n := len(tmp) - 1
+	tmp[n] = byte(v & 0x7f)
+	for v >>= 7; v > 0; v >>= 7 {
+		n--
+		tmp[n] = byte(v&0x7f) | 0x80
+	}
+	return append(b, tmp[n:]...)
+}
@@ -0,0 +1,61 @@
+package midi
+
+import (
+	"math"
+	"sort"
+)
+
+// tempoChange is a tempo change at a time in seconds, and once
+// resolved, at a tick.
+type tempoChange struct {
+	time float64
+	tick uint32
+	bpm  float64
+}
+
+// resolveTempos sorts the tempo changes, makes sure one starts at
+// zero and computes the tick each one falls on.
+func resolveTempos(tempos []tempoChange, division uint16) []tempoChange {
+	out := append([]tempoChange(nil), tempos...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].time < out[j].time })
+	if len(out) == 0 || out[0].time > 0 {
+		out = append([]tempoChange{{bpm: DefaultBPM}}, out...)
+	}
+	out[0].time = 0
+	var ticks float64
+	for i := range out {
+		if i > 0 {
+			ticks += (out[i].time - out[i-1].time) * out[i-1].bpm / 60 * float64(division)
+		}
+		out[i].tick = uint32(math.Round(ticks))
+	}
+	return out
+}
+
+// secondsToTicks converts a time in seconds to ticks using resolved
+// tempo changes.
+func secondsToTicks(tempos []tempoChange, t float64, division uint16) uint32 {
+	if t <= 0 {
+		return 0
+	}
+	i := sort.Search(len(tempos), func(i int) bool { return tempos[i].time > t }) - 1
+	tc := tempos[i]
+	ticks := float64(tc.tick) + (t-tc.time)*tc.bpm/60*float64(division)
+	return uint32(math.Round(ticks))
+}
+
+// ticksToSeconds converts ticks to seconds using tempo changes
+// located by tick.
+func ticksToSeconds(tempos []tempoChange, tick uint32, division uint16) float64 {
+	sort.SliceStable(tempos, func(i, j int) bool { return tempos[i].tick < tempos[j].tick })
+	var seconds float64
+	last, bpm := uint32(0), DefaultBPM
+	for _, tc := range tempos {
+		if tc.tick > tick {
+			break
+		}
+		seconds += float64(tc.tick-last) / float64(division) * 60 / bpm
+		last, bpm = tc.tick, tc.bpm
+	}
+	return seconds + float64(tick-last)/float64(division)*60/bpm
+}
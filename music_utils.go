@@ -61,3 +61,36 @@ func Unwrap2pi(phase float64) float64 {
 func ZeroCrossingRate(buf *SimpleBuffer) float64 {
 	return float64(C.aubio_zero_crossing_rate(buf.vec))
 }
+
+// Convert frequency (Hz) to midi value (0-128).
+// Frequencies below 2Hz or above 100kHz return 0.
+func FreqToMidi(freq float64) float64 {
+	return float64(C.aubio_freqtomidi(C.smpl_t(freq)))
+}
+
+// Convert midi value (0-128) to frequency (Hz).
+// Midi values above 140 return 0.
+func MidiToFreq(midi float64) float64 {
+	return float64(C.aubio_miditofreq(C.smpl_t(midi)))
+}
+
+// Convert a bin of a bufSize spectrum, such as the output of PhaseVoc,
+// to frequency (Hz). The arguments follow the order of aubio_bintofreq.
+func BinToFreq(bin float64, samplerate, bufSize uint) float64 {
+	return float64(C.aubio_bintofreq(C.smpl_t(bin), C.smpl_t(samplerate), C.smpl_t(bufSize)))
+}
+
+// Convert frequency (Hz) to a bin of a bufSize spectrum.
+func FreqToBin(freq float64, samplerate, bufSize uint) float64 {
+	return float64(C.aubio_freqtobin(C.smpl_t(freq), C.smpl_t(samplerate), C.smpl_t(bufSize)))
+}
+
+// Convert a bin of a bufSize spectrum to midi value.
+func BinToMidi(bin float64, samplerate, bufSize uint) float64 {
+	return float64(C.aubio_bintomidi(C.smpl_t(bin), C.smpl_t(samplerate), C.smpl_t(bufSize)))
+}
+
+// Convert midi value to a bin of a bufSize spectrum.
+func MidiToBin(midi float64, samplerate, bufSize uint) float64 {
+	return float64(C.aubio_miditobin(C.smpl_t(midi), C.smpl_t(samplerate), C.smpl_t(bufSize)))
+}
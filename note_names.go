@@ -0,0 +1,73 @@
+package aubio
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatNote returns the name of a midi note in scientific pitch
+// notation using sharps, where 60 is "C4" and 69 is "A4".
+func FormatNote(midi int) string {
+	pc := ((midi % 12) + 12) % 12
+	octave := (midi - pc) / 12
+	return pitchClassNames[pc] + strconv.Itoa(octave-1)
+}
+
+// FormatNoteFlat is like FormatNote but spells accidentals as flats.
+func FormatNoteFlat(midi int) string {
+	name := FormatNote(midi)
+	if len(name) < 2 || name[1] != '#' {
+		return name
+	}
+	return string("CDEFGAB"[strings.IndexByte("CDEFGAB", name[0])+1]) + "b" + name[2:]
+}
+
+// ParseNote returns the midi note named by s in scientific pitch
+// notation, such as "A4", "A#4", "Bb3" or "C-1". Any number of sharps
+// (#, ♯) or flats (b, ♭) may follow the letter.
+func ParseNote(s string) (int, error) {
+	name := strings.TrimSpace(s)
+	if name == "" {
+		return 0, fmt.Errorf("invalid note name %q", s)
+	}
+	// semitones above C of each letter, spaces fill the accidentals
+	pc := strings.IndexByte("C D EF G A B", strings.ToUpper(name)[0])
+	if pc < 0 {
+		return 0, fmt.Errorf("invalid note name %q", s)
+	}
+	rest := name[1:]
+	for rest != "" {
+		r, size := utf8.DecodeRuneInString(rest)
+		if r == '#' || r == '♯' {
+			pc++
+		} else if r == 'b' || r == '♭' {
+			pc--
+		} else {
+			break
+		}
+		rest = rest[size:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid octave in note name %q", s)
+	}
+	return 12*(octave+1) + pc, nil
+}
+
+// Cents returns the interval in cents from the frequency from to the
+// frequency to.
+func Cents(from, to float64) float64 {
+	return 1200 * math.Log2(to/from)
+}
+
+// NearestNote returns the equal tempered midi note closest to freq,
+// with A4 tuned to reference Hz, and how many cents freq is away
+// from it, between -50 and 50.
+func NearestNote(freq, reference float64) (midi int, cents float64) {
+	exact := 69 + 12*math.Log2(freq/reference)
+	midi = int(math.Round(exact))
+	return midi, 100 * (exact - float64(midi))
+}
@@ -0,0 +1,73 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoteNames(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		midi int
+	}{
+		{"C4", 60}, {"A4", 69}, {"A#4", 70}, {"Bb3", 58}, {"B♭3", 58},
+		{"c#2", 37}, {"Cb4", 59}, {"E#4", 65}, {"C-1", 0}, {"G9", 127}, {"F##3", 55},
+	} {
+		got, err := ParseNote(tc.name)
+		if err != nil || got != tc.midi {
+			t.Errorf("ParseNote(%q) = %d, %v, want %d", tc.name, got, err, tc.midi)
+		}
+	}
+	for _, bad := range []string{"", "H4", "A", "#4", "Ax4"} {
+		if _, err := ParseNote(bad); err == nil {
+			t.Errorf("ParseNote(%q) should fail", bad)
+		}
+	}
+	for midi := 0; midi < 128; midi++ {
+		for _, format := range []func(int) string{FormatNote, FormatNoteFlat} {
+			name := format(midi)
+			if got, err := ParseNote(name); err != nil || got != midi {
+				t.Errorf("ParseNote(%q) = %d, %v, want %d", name, got, err, midi)
+			}
+		}
+	}
+	if got := FormatNote(70); got != "A#4" {
+		t.Errorf("FormatNote(70) = %q", got)
+	}
+	if got := FormatNoteFlat(70); got != "Bb4" {
+		t.Errorf("FormatNoteFlat(70) = %q", got)
+	}
+}
+
+func TestCents(t *testing.T) {
+	if got := Cents(440, 880); math.Abs(got-1200) > 1e-9 {
+		t.Errorf("Cents(440, 880) = %v", got)
+	}
+	midi, cents := NearestNote(445, 440)
+	if midi != 69 || math.Abs(cents-Cents(440, 445)) > 1e-9 {
+		t.Errorf("NearestNote(445) = %d, %v", midi, cents)
+	}
+	midi, cents = NearestNote(430, 440)
+	if midi != 69 || cents >= 0 {
+		t.Errorf("NearestNote(430) = %d, %v", midi, cents)
+	}
+}
+
+func TestMusicConversions(t *testing.T) {
+	if got := MidiToFreq(69); math.Abs(got-440) > 1e-3 {
+		t.Errorf("MidiToFreq(69) = %v", got)
+	}
+	if got := FreqToMidi(440); math.Abs(got-69) > 1e-3 {
+		t.Errorf("FreqToMidi(440) = %v", got)
+	}
+	bin := FreqToBin(440, testSamplerate, testBufSize)
+	if want := 440. * testBufSize / testSamplerate; math.Abs(bin-want) > 1e-3 {
+		t.Errorf("FreqToBin(440) = %v, want %v", bin, want)
+	}
+	if got := BinToFreq(bin, testSamplerate, testBufSize); math.Abs(got-440) > 1e-2 {
+		t.Errorf("BinToFreq(%v) = %v", bin, got)
+	}
+	if got := BinToMidi(MidiToBin(60, testSamplerate, testBufSize), testSamplerate, testBufSize); math.Abs(got-60) > 1e-3 {
+		t.Errorf("BinToMidi(MidiToBin(60)) = %v", got)
+	}
+}
@@ -0,0 +1,29 @@
+package aubio
+
+import "math"
+
+// NoteEvent is a MIDI style note-on or note-off event produced by Notes.
+type NoteEvent struct {
+	// On is true for a note-on event and false for a note-off event.
+	On       bool
+	MidiNote int
+	// Velocity is 0 for note-off events.
+	Velocity int
+	// Time is the start of the hop the event was detected in, in seconds.
+	Time float64
+}
+
+// noteEvents converts the note-on, velocity and note-off triple
+// produced by aubio_notes_do into events. A note-off always comes
+// before the note-on it makes room for. Notes are rounded to the
+// nearest midi note.
+func noteEvents(on, velocity, off, t float64) []NoteEvent {
+	var events []NoteEvent
+	if off > 0 {
+		events = append(events, NoteEvent{On: false, MidiNote: int(math.Round(off)), Time: t})
+	}
+	if on > 0 {
+		events = append(events, NoteEvent{On: true, MidiNote: int(math.Round(on)), Velocity: int(velocity), Time: t})
+	}
+	return events
+}
@@ -0,0 +1,147 @@
+//go:build !aubio_nonotes
+
+package aubio
+
+/*
+#cgo LDFLAGS: -laubio
+#include <aubio/aubio.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"log"
+)
+
+// Notes is a wrapper for the aubio_notes_t note tracking object.
+// It combines onset and pitch detection into note-on and note-off
+// events.
+//
+// Build with the aubio_nonotes tag when the linked aubio predates
+// aubio_notes_t to use a pure Go implementation built from Onset and
+// Pitch instead.
+type Notes struct {
+	o          *C.aubio_notes_t
+	buf        *SimpleBuffer
+	hopSize    uint
+	samplerate uint
+	frames     uint
+	events     []NoteEvent
+}
+
+// NotesOrDie constructs a new Notes object.
+// It panics on any errors.
+func NotesOrDie(bufSize, hopSize, samplerate uint) *Notes {
+	if n, err := NewNotes(bufSize, hopSize, samplerate); err == nil {
+		return n
+	} else {
+		panic(err)
+	}
+}
+
+// NewNotes constructs a new Notes object.
+// It is the Callers responsibility to call Free on the returned
+// Notes object or leak memory.
+//     n, err := NewNotes(bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer n.Free()
+func NewNotes(bufSize, hopSize, samplerate uint) (*Notes, error) {
+	n, err := C.new_aubio_notes(toCharTPtr("default"),
+		C.uint_t(bufSize), C.uint_t(hopSize), C.uint_t(samplerate))
+	if n == nil {
+		return nil, fmt.Errorf("failure creating Notes object %q", err)
+	}
+	return &Notes{
+		o:          n,
+		buf:        NewSimpleBuffer(3),
+		hopSize:    hopSize,
+		samplerate: samplerate,
+	}, nil
+}
+
+// Buffer returns the raw output of the latest call to Do: the
+// note-on midi note, its velocity and the note-off midi note.
+func (n *Notes) Buffer() *SimpleBuffer {
+	return n.buf
+}
+
+// Do runs the note tracking on a hopSize input Buffer.
+// The events it detects are available from Events.
+func (n *Notes) Do(input *SimpleBuffer) {
+	if n.o == nil {
+		log.Println("Called Do on empty Notes. Maybe you called Free previously?")
+		return
+	}
+	C.aubio_notes_do(n.o, input.vec, n.buf.vec)
+	t := float64(n.frames) / float64(n.samplerate)
+	n.frames += n.hopSize
+	n.events = noteEvents(n.buf.Get(0), n.buf.Get(1), n.buf.Get(2), t)
+}
+
+// Events returns the note events detected by the latest call to Do.
+func (n *Notes) Events() []NoteEvent {
+	return n.events
+}
+
+// SetSilence sets the silence threshold in dB.
+func (n *Notes) SetSilence(silence float64) {
+	if n.o == nil {
+		return
+	}
+	C.aubio_notes_set_silence(n.o, C.smpl_t(silence))
+}
+
+// GetSilence returns the silence threshold in dB.
+func (n *Notes) GetSilence() float64 {
+	if n.o == nil {
+		return 0
+	}
+	return float64(C.aubio_notes_get_silence(n.o))
+}
+
+// SetMinioiMs sets the minimum inter onset interval in milliseconds.
+func (n *Notes) SetMinioiMs(minioi float64) {
+	if n.o == nil {
+		return
+	}
+	C.aubio_notes_set_minioi_ms(n.o, C.smpl_t(minioi))
+}
+
+// GetMinioiMs returns the minimum inter onset interval in milliseconds.
+func (n *Notes) GetMinioiMs() float64 {
+	if n.o == nil {
+		return 0
+	}
+	return float64(C.aubio_notes_get_minioi_ms(n.o))
+}
+
+// SetReleaseDrop sets the level drop in dB, relative to the level at
+// the onset, after which a note-off is sent.
+func (n *Notes) SetReleaseDrop(drop float64) {
+	if n.o == nil {
+		return
+	}
+	C.aubio_notes_set_release_drop(n.o, C.smpl_t(drop))
+}
+
+// GetReleaseDrop returns the release drop in dB.
+func (n *Notes) GetReleaseDrop() float64 {
+	if n.o == nil {
+		return 0
+	}
+	return float64(C.aubio_notes_get_release_drop(n.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (n *Notes) Free() {
+	if n.o != nil {
+		C.del_aubio_notes(n.o)
+		n.o = nil
+	}
+	if n.buf != nil {
+		n.buf.Free()
+		n.buf = nil
+	}
+}
@@ -0,0 +1,221 @@
+//go:build aubio_nonotes
+
+package aubio
+
+import (
+	"log"
+	"math"
+	"sort"
+)
+
+// Defaults used by aubio_notes_t.
+const (
+	notesMedian    = 6
+	notesSilence   = -70.
+	notesMinioiMs  = 30.
+	notesLevelInit = -70.
+	notesMinNote   = 45
+	notesRelease   = 10.
+)
+
+// Notes tracks notes using Onset and Pitch, following the algorithm
+// of aubio_notes_t for builds against an aubio without it.
+// It combines onset and pitch detection into note-on and note-off
+// events.
+type Notes struct {
+	onset      *Onset
+	pitch      *Pitch
+	buf        *SimpleBuffer
+	hopSize    uint
+	samplerate uint
+	frames     uint
+	events     []NoteEvent
+
+	notes          []float64
+	isReady        int
+	curNote        float64
+	silence        float64
+	releaseDrop    float64
+	lastOnsetLevel float64
+}
+
+// NotesOrDie constructs a new Notes object.
+// It panics on any errors.
+func NotesOrDie(bufSize, hopSize, samplerate uint) *Notes {
+	if n, err := NewNotes(bufSize, hopSize, samplerate); err == nil {
+		return n
+	} else {
+		panic(err)
+	}
+}
+
+// NewNotes constructs a new Notes object.
+// It is the Callers responsibility to call Free on the returned
+// Notes object or leak memory.
+//     n, err := NewNotes(bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer n.Free()
+func NewNotes(bufSize, hopSize, samplerate uint) (*Notes, error) {
+	onset, err := NewOnset(OnsetDefault, bufSize, hopSize, samplerate)
+	if err != nil {
+		return nil, err
+	}
+	pitch, err := NewPitch(PitchDefault, bufSize*4, hopSize, samplerate)
+	if err != nil {
+		onset.Free()
+		return nil, err
+	}
+	if err := pitch.SetUnit(PitchOutMidi); err != nil {
+		onset.Free()
+		pitch.Free()
+		return nil, err
+	}
+	n := &Notes{
+		onset:          onset,
+		pitch:          pitch,
+		buf:            NewSimpleBuffer(3),
+		hopSize:        hopSize,
+		samplerate:     samplerate,
+		notes:          make([]float64, notesMedian),
+		curNote:        -1,
+		releaseDrop:    notesRelease,
+		lastOnsetLevel: notesLevelInit,
+	}
+	n.SetSilence(notesSilence)
+	n.SetMinioiMs(notesMinioiMs)
+	return n, nil
+}
+
+// Buffer returns the raw output of the latest call to Do: the
+// note-on midi note, its velocity and the note-off midi note.
+func (n *Notes) Buffer() *SimpleBuffer {
+	return n.buf
+}
+
+// Do runs the note tracking on a hopSize input Buffer.
+// The events it detects are available from Events.
+func (n *Notes) Do(input *SimpleBuffer) {
+	if n.onset == nil {
+		log.Println("Called Do on empty Notes. Maybe you called Free previously?")
+		return
+	}
+	n.onset.Do(input)
+	n.pitch.Do(input)
+	// level is negative, or 1 when silent
+	level := LevelDetection(input, n.silence)
+	on, velocity, off := n.step(n.onset.OnsetNow(), level, n.pitch.Buffer().Get(0))
+	n.buf.SetData([]float64{on, velocity, off})
+	t := float64(n.frames) / float64(n.samplerate)
+	n.frames += n.hopSize
+	n.events = noteEvents(on, velocity, off, t)
+}
+
+// step advances the note state machine of aubio_notes_do by one hop,
+// given whether an onset was detected, the level of the hop and its
+// pitch estimate, and returns the note-on, velocity and note-off.
+func (n *Notes) step(onset bool, level, pitch float64) (on, velocity, off float64) {
+	// like note_append, keep the estimates rounded to the nearest note
+	n.notes = append(n.notes[1:], math.Floor(pitch+0.5))
+	if onset {
+		if level == 1 {
+			n.isReady = 0
+			off = n.curNote
+		} else {
+			n.isReady = 1
+			n.lastOnsetLevel = level
+		}
+	} else if level < n.lastOnsetLevel-n.releaseDrop {
+		off = n.curNote
+		n.lastOnsetLevel = n.silence
+		n.curNote = 0
+	} else {
+		if n.isReady > 0 {
+			n.isReady++
+		}
+		if n.isReady == notesMedian {
+			if n.curNote != 0 {
+				off = n.curNote
+			}
+			n.curNote = n.latestNote()
+			if n.curNote > notesMinNote {
+				on = n.curNote
+				velocity = 127 + math.Floor(level)
+			}
+		}
+	}
+	return on, velocity, off
+}
+
+// latestNote returns the median of the recent rounded pitch estimates.
+// Like fvec_median, it takes the lower of the two middle values of an
+// even count rather than their mean.
+func (n *Notes) latestNote() float64 {
+	s := append([]float64(nil), n.notes...)
+	sort.Float64s(s)
+	return s[(len(s)-1)/2]
+}
+
+// Events returns the note events detected by the latest call to Do.
+func (n *Notes) Events() []NoteEvent {
+	return n.events
+}
+
+// SetSilence sets the silence threshold in dB.
+func (n *Notes) SetSilence(silence float64) {
+	if n.onset == nil {
+		return
+	}
+	n.onset.SetSilence(silence)
+	n.pitch.SetSilence(silence)
+	n.silence = silence
+}
+
+// GetSilence returns the silence threshold in dB.
+func (n *Notes) GetSilence() float64 {
+	return n.silence
+}
+
+// SetMinioiMs sets the minimum inter onset interval in milliseconds.
+func (n *Notes) SetMinioiMs(minioi float64) {
+	if n.onset == nil {
+		return
+	}
+	n.onset.SetMinioiMs(minioi)
+}
+
+// GetMinioiMs returns the minimum inter onset interval in milliseconds.
+func (n *Notes) GetMinioiMs() float64 {
+	if n.onset == nil {
+		return 0
+	}
+	return n.onset.GetMinioiMs()
+}
+
+// SetReleaseDrop sets the level drop in dB, relative to the level at
+// the onset, after which a note-off is sent.
+func (n *Notes) SetReleaseDrop(drop float64) {
+	n.releaseDrop = drop
+}
+
+// GetReleaseDrop returns the release drop in dB.
+func (n *Notes) GetReleaseDrop() float64 {
+	return n.releaseDrop
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (n *Notes) Free() {
+	if n.onset != nil {
+		n.onset.Free()
+		n.onset = nil
+	}
+	if n.pitch != nil {
+		n.pitch.Free()
+		n.pitch = nil
+	}
+	if n.buf != nil {
+		n.buf.Free()
+		n.buf = nil
+	}
+}
@@ -0,0 +1,92 @@
+//go:build aubio_nonotes
+
+package aubio
+
+import "testing"
+
+func newTestNotes() *Notes {
+	return &Notes{
+		notes:          make([]float64, notesMedian),
+		curNote:        -1,
+		silence:        notesSilence,
+		releaseDrop:    notesRelease,
+		lastOnsetLevel: notesLevelInit,
+	}
+}
+
+func TestNotesFallbackStep(t *testing.T) {
+	n := newTestNotes()
+	if on, _, _ := n.step(true, -20, 60); on != 0 {
+		t.Errorf("note-on %v at the onset, want it once the median is full", on)
+	}
+	// the note starts once notesMedian pitch estimates were gathered
+	for i := 1; i < notesMedian-1; i++ {
+		if on, _, _ := n.step(false, -20, 60); on != 0 {
+			t.Fatalf("hop %d: early note-on %v", i, on)
+		}
+	}
+	on, velocity, _ := n.step(false, -20, 60)
+	if on != 60 || velocity != 107 {
+		t.Errorf("got note-on %v velocity %v, want 60 and 107", on, velocity)
+	}
+	// a drop of more than the release ends it
+	if _, _, off := n.step(false, -35, 60); off != 60 {
+		t.Errorf("got note-off %v, want 60", off)
+	}
+	if n.lastOnsetLevel != notesSilence {
+		t.Errorf("level after release %v, want the silence threshold %v", n.lastOnsetLevel, notesSilence)
+	}
+	// an onset in silence ends the current note
+	n = newTestNotes()
+	n.curNote = 64
+	if _, _, off := n.step(true, 1, 0); off != 64 {
+		t.Errorf("got note-off %v on a silent onset, want 64", off)
+	}
+}
+
+func TestNotesFallbackIgnoresLowNotes(t *testing.T) {
+	n := newTestNotes()
+	n.step(true, -20, 40)
+	for i := 1; i < notesMedian; i++ {
+		if on, _, _ := n.step(false, -20, 40); on != 0 {
+			t.Errorf("got note-on %v below the lowest note", on)
+		}
+	}
+}
+
+func TestNotesFallbackLowerMedian(t *testing.T) {
+	n := newTestNotes()
+	n.step(true, -20, 60)
+	// half of the estimates at 60 and half at 61
+	var on float64
+	for i := 1; i < notesMedian; i++ {
+		pitch := 60.0
+		if i%2 == 1 {
+			pitch = 61
+		}
+		on, _, _ = n.step(false, -20, pitch)
+	}
+	if on != 60 {
+		t.Errorf("got note-on %v, want the lower median 60", on)
+	}
+}
+
+func TestNotesFallbackRoundsEstimates(t *testing.T) {
+	n := newTestNotes()
+	n.step(true, -20, 60.3)
+	var on float64
+	for i := 1; i < notesMedian; i++ {
+		on, _, _ = n.step(false, -20, 60.3)
+	}
+	if on != 60 {
+		t.Errorf("got note-on %v, want the rounded 60", on)
+	}
+	// rounded down to the lowest note, which is excluded
+	n = newTestNotes()
+	n.step(true, -20, 45.3)
+	for i := 1; i < notesMedian; i++ {
+		if on, _, _ := n.step(false, -20, 45.3); on != 0 {
+			t.Errorf("got note-on %v for estimates rounding to the lowest note", on)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package aubio
+
+import "testing"
+
+func TestNoteEvents(t *testing.T) {
+	if events := noteEvents(0, 0, 0, 1); len(events) != 0 {
+		t.Errorf("got %v, want no events", events)
+	}
+	events := noteEvents(60.6, 100, 57.4, 1.5)
+	want := []NoteEvent{
+		{On: false, MidiNote: 57, Time: 1.5},
+		{On: true, MidiNote: 61, Velocity: 100, Time: 1.5},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+// toneBurst returns silence, then a tone at freq Hz held for hold
+// seconds and faded out linearly over fade seconds, then silence.
+func toneBurst(freq, hold, fade float64, samplerate int) []float64 {
+	start := samplerate / 4
+	held := int(hold * float64(samplerate))
+	faded := int(fade * float64(samplerate))
+	out := make([]float64, start+held+faded+samplerate/2)
+	burst := tone(freq, samplerate, held+faded)
+	for i, x := range burst {
+		if i >= held {
+			x *= 1 - float64(i-held)/float64(faded)
+		}
+		out[start+i] = x
+	}
+	return out
+}
+
+func TestNotesToneBurst(t *testing.T) {
+	n, err := NewNotes(testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Free()
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	var events []NoteEvent
+	signal := toneBurst(440, 0.5, 0.5, testSamplerate)
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		buf.SetData(signal[i : i+testHopSize])
+		n.Do(buf)
+		events = append(events, n.Events()...)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got events %+v, want one note-on and one note-off", events)
+	}
+	on, off := events[0], events[1]
+	if !on.On || on.MidiNote != 69 || on.Velocity <= 0 {
+		t.Errorf("got first event %+v, want a note-on of 69", on)
+	}
+	if off.On || off.MidiNote != 69 {
+		t.Errorf("got second event %+v, want a note-off of 69", off)
+	}
+	if on.Time < 0.25 || off.Time <= on.Time {
+		t.Errorf("note-on at %vs and note-off at %vs, want the note-on after 0.25s and before the note-off", on.Time, off.Time)
+	}
+}
@@ -0,0 +1,174 @@
+package aubio
+
+import (
+	"errors"
+	"log"
+	"math"
+)
+
+// NoveltyBeatTracker tracks beats on any onset detection function, fed
+// one value per hop: a SpecDesc output, the flux of a single band, or
+// Onset.GetDescriptor. It maintains the rolling detection function
+// window BeatTracker expects, and calls it at the same cadence as
+// aubio_tempo does.
+type NoveltyBeatTracker struct {
+	bt         *BeatTracker
+	pp         *PeakPicker
+	window     *SimpleBuffer
+	df         []float64
+	step       int
+	blockpos   int
+	hopSize    uint
+	samplerate uint
+	frames     uint
+	beat       bool
+	last       uint
+
+	// Raw disables the adaptive threshold of the PeakPicker applied to
+	// the novelty values, for curves that are already thresholded.
+	Raw bool
+}
+
+// NewNoveltyBeatTracker constructs a new NoveltyBeatTracker for
+// novelty values computed every hopSize samples.
+// It is the Callers responsibility to call Free on the returned
+// NoveltyBeatTracker object or leak memory.
+//     nbt, err := NewNoveltyBeatTracker(hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer nbt.Free()
+func NewNoveltyBeatTracker(hopSize, samplerate uint) (*NoveltyBeatTracker, error) {
+	if hopSize == 0 || samplerate == 0 {
+		return nil, errors.New("novelty beat tracker needs a hop size and a samplerate")
+	}
+	// same window as aubio_tempo, about 6 seconds of hops
+	winlen := uint(4)
+	for float64(winlen) < 5.8*float64(samplerate)/float64(hopSize) {
+		winlen <<= 1
+	}
+	bt, err := NewBeatTracker(winlen, hopSize, samplerate)
+	if err != nil {
+		return nil, err
+	}
+	pp, err := NewPeakPicker()
+	if err != nil {
+		bt.Free()
+		return nil, err
+	}
+	// the default threshold of aubio_tempo
+	pp.SetThreshold(0.3)
+	return &NoveltyBeatTracker{
+		bt:         bt,
+		pp:         pp,
+		window:     NewSimpleBuffer(winlen),
+		df:         make([]float64, winlen),
+		step:       int(winlen / 4),
+		hopSize:    hopSize,
+		samplerate: samplerate,
+	}, nil
+}
+
+// BeatTracker returns the underlying BeatTracker.
+func (nbt *NoveltyBeatTracker) BeatTracker() *BeatTracker {
+	return nbt.bt
+}
+
+// PeakPicker returns the PeakPicker thresholding the novelty values,
+// for instance to change its threshold.
+func (nbt *NoveltyBeatTracker) PeakPicker() *PeakPicker {
+	return nbt.pp
+}
+
+// Do adds the novelty value of the next hop and predicts whether a
+// beat falls within it.
+func (nbt *NoveltyBeatTracker) Do(novelty float64) {
+	if nbt.bt == nil {
+		log.Println("Called Do on empty NoveltyBeatTracker. Maybe you called Free previously?")
+		return
+	}
+	winlen := len(nbt.df)
+	if nbt.blockpos == nbt.step-1 {
+		nbt.window.SetData(nbt.df)
+		nbt.bt.Do(nbt.window)
+		// rotate the window by one step
+		copy(nbt.df, nbt.df[nbt.step:])
+		for i := winlen - nbt.step; i < winlen; i++ {
+			nbt.df[i] = 0
+		}
+		nbt.blockpos = -1
+	}
+	nbt.blockpos++
+	if !nbt.Raw {
+		nbt.pp.DoValue(novelty)
+		novelty = nbt.pp.Thresholded()
+	}
+	nbt.df[winlen-nbt.step+nbt.blockpos] = novelty
+
+	nbt.beat = false
+	out := nbt.bt.Buffer().Slice()
+	for i := 1; i < int(out[0]) && i < len(out); i++ {
+		if nbt.blockpos == int(math.Floor(out[i])) {
+			frac := out[i] - math.Floor(out[i])
+			nbt.beat = true
+			nbt.last = nbt.frames + uint(math.Round(frac*float64(nbt.hopSize)))
+		}
+	}
+	nbt.frames += nbt.hopSize
+}
+
+// IsBeat returns if a beat was predicted in the hop of the latest
+// call to Do.
+func (nbt *NoveltyBeatTracker) IsBeat() bool {
+	return nbt.beat
+}
+
+// GetLast returns the time of the latest beat, in samples.
+func (nbt *NoveltyBeatTracker) GetLast() uint {
+	return nbt.last
+}
+
+// GetLastS returns the time of the latest beat, in seconds.
+func (nbt *NoveltyBeatTracker) GetLastS() float64 {
+	return float64(nbt.last) / float64(nbt.samplerate)
+}
+
+// GetBpm returns the current tempo in beats per minute.
+func (nbt *NoveltyBeatTracker) GetBpm() float64 {
+	if nbt.bt == nil {
+		return 0
+	}
+	return nbt.bt.GetBpm()
+}
+
+// GetPeriodS returns the current beat period in seconds.
+func (nbt *NoveltyBeatTracker) GetPeriodS() float64 {
+	if nbt.bt == nil {
+		return 0
+	}
+	return nbt.bt.GetPeriodS()
+}
+
+// GetConfidence returns the confidence of the current tempo.
+func (nbt *NoveltyBeatTracker) GetConfidence() float64 {
+	if nbt.bt == nil {
+		return 0
+	}
+	return nbt.bt.GetConfidence()
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (nbt *NoveltyBeatTracker) Free() {
+	if nbt.bt != nil {
+		nbt.bt.Free()
+		nbt.bt = nil
+	}
+	if nbt.pp != nil {
+		nbt.pp.Free()
+		nbt.pp = nil
+	}
+	if nbt.window != nil {
+		nbt.window.Free()
+		nbt.window = nil
+	}
+}
@@ -0,0 +1,68 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoveltyBeatTracker(t *testing.T) {
+	nbt, err := NewNoveltyBeatTracker(testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nbt.Free()
+	if got := nbt.BeatTracker().Buffer().Size(); got != uint(len(nbt.df))/4+1 {
+		t.Errorf("beat tracker output has %d samples, want %d", got, len(nbt.df)/4+1)
+	}
+	// a novelty impulse every beat at 120 BPM
+	hopS := float64(testHopSize) / testSamplerate
+	var beats []float64
+	for i := 0; float64(i)*hopS < 20; i++ {
+		novelty := 0.
+		if math.Mod(float64(i)*hopS, 0.5) < hopS {
+			novelty = 1
+		}
+		nbt.Do(novelty)
+		if nbt.IsBeat() {
+			beats = append(beats, nbt.GetLastS())
+		}
+	}
+	if math.Abs(nbt.GetBpm()-120) > 2 {
+		t.Errorf("BPM = %v, want 120", nbt.GetBpm())
+	}
+	bt := nbt.BeatTracker()
+	if got, want := bt.GetPeriodS(), bt.GetPeriod()/testSamplerate; math.Abs(got-want) > 1e-6 {
+		t.Errorf("period %vs, want %v samples at %dHz", got, bt.GetPeriod(), testSamplerate)
+	}
+	if math.Abs(bt.GetPeriodS()-0.5) > 2*hopS {
+		t.Errorf("period %vs, want 0.5s", bt.GetPeriodS())
+	}
+	if len(beats) < 20 {
+		t.Fatalf("got %d beats, want about 30", len(beats))
+	}
+	for i := len(beats) - 10; i < len(beats); i++ {
+		if d := beats[i] - beats[i-1]; math.Abs(d-0.5) > 2*hopS {
+			t.Errorf("beats %v and %v are %vs apart, want 0.5s", beats[i-1], beats[i], d)
+		}
+	}
+}
+
+func TestNoveltyBeatTrackerInvalid(t *testing.T) {
+	if _, err := NewNoveltyBeatTracker(0, testSamplerate); err == nil {
+		t.Error("got no error for a zero hop size")
+	}
+	if _, err := NewNoveltyBeatTracker(testHopSize, 0); err == nil {
+		t.Error("got no error for a zero samplerate")
+	}
+}
+
+func TestNoveltyBeatTrackerFree(t *testing.T) {
+	nbt, err := NewNoveltyBeatTracker(testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nbt.Free()
+	if bpm, period, conf := nbt.GetBpm(), nbt.GetPeriodS(), nbt.GetConfidence(); bpm != 0 || period != 0 || conf != 0 {
+		t.Errorf("got %v, %v and %v after Free, want 0", bpm, period, conf)
+	}
+}
@@ -21,28 +21,6 @@ import (
 	"fmt"
 )
 
-type onsetMode string
-
-const (
-	// Onset Detection functions see: https://github.com/piem/aubio/blob/develop/src/spectral/specdesc.h
-	// Energy based onset detection function
-	Energy onsetMode = "energy"
-	// High Frequency Content onset detection function
-	HFC onsetMode = "hfc"
-	// Complex Domain Method onset detection function
-	Complex onsetMode = "complex"
-	// Phase based Method onset detection function
-	Phase onsetMode = "phase"
-	// Spectral difference method onset detection function
-	SpecDiff onsetMode = "specdiff"
-	// Kullback-Liebler onset detection function
-	K1 onsetMode = "k1"
-	// Modified Kullback-Liebler onset detection function
-	MK1 onsetMode = "mk1"
-	// Spectral Flux
-	SpecFlux onsetMode = "specflux"
-)
-
 // Tempo is a wrapper for the aubio_tempo_t tempo detection object.
 type Onset struct {
 	o   *C.aubio_onset_t
@@ -51,7 +29,7 @@ type Onset struct {
 
 // OnsetOrDie constructs a new Onset object.
 // It panics on any errors.
-func OnsetOrDie(mode onsetMode, bufSize, blocksize, samplerate uint) *Onset {
+func OnsetOrDie(mode OnsetMethod, bufSize, blocksize, samplerate uint) *Onset {
 	if t, err := NewOnset(mode, bufSize, blocksize, samplerate); err == nil {
 		return t
 	} else {
@@ -68,7 +46,10 @@ func OnsetOrDie(mode onsetMode, bufSize, blocksize, samplerate uint) *Onset {
 //     }
 //     defer t.Free()
 func NewOnset(
-	onset_mode onsetMode, bufSize, blockSize, samplerate uint) (*Onset, error) {
+	onset_mode OnsetMethod, bufSize, blockSize, samplerate uint) (*Onset, error) {
+	if err := onset_mode.validate(); err != nil {
+		return nil, err
+	}
 	t, err := C.new_aubio_onset(toCharTPtr(string(onset_mode)),
 		C.uint_t(bufSize), C.uint_t(blockSize), C.uint_t(samplerate))
 	if t == nil {
@@ -111,21 +92,84 @@ func (t *Onset) OnsetNow() bool {
 	return C.fvec_get_sample(t.buf.vec, C.uint_t(0)) != 0
 }
 
-/* TODO(jwall): Update to the latest version of the aubio library
-// GetLastOnset returns the bpm after running Do on an input Buffer
-//     t, err := NewOnset(mode, bufSize, blockSize, samplerate)
-//      if err != nil {
-//      }
-//      defer t.Close()
-//      t.Do(buf)
-//      fmt.Println("BPM: ", t.GetBpm())
-func (t *Onset) GetLastOnset() float64 {
+// GetThreshold returns the onset detection peak picking threshold.
+func (t *Onset) GetThreshold() float64 {
 	if t.o == nil {
 		return 0
 	}
-	return float64(C.aubio_onset_get_last_onset(t.o))
+	return float64(C.aubio_onset_get_threshold(t.o))
+}
+
+// GetSilence returns the onset detection silence threshold in dB.
+func (t *Onset) GetSilence() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_silence(t.o))
+}
+
+// SetMinioiMs sets the minimum inter onset interval in milliseconds.
+func (t *Onset) SetMinioiMs(minioi float64) {
+	if t.o == nil {
+		return
+	}
+	C.aubio_onset_set_minioi_ms(t.o, C.smpl_t(minioi))
+}
+
+// GetMinioiMs returns the minimum inter onset interval in milliseconds.
+func (t *Onset) GetMinioiMs() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_minioi_ms(t.o))
+}
+
+// GetDescriptor returns the value of the onset detection function
+// computed by the latest call to Do.
+func (t *Onset) GetDescriptor() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_descriptor(t.o))
+}
+
+// GetThresholdedDescriptor returns the value of the onset detection
+// function computed by the latest call to Do, after the adaptive
+// threshold of the peak picker was subtracted.
+func (t *Onset) GetThresholdedDescriptor() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_thresholded_descriptor(t.o))
+}
+
+// GetLast returns the time of the latest onset detected, in samples.
+func (t *Onset) GetLast() uint {
+	if t.o == nil {
+		return 0
+	}
+	return uint(C.aubio_onset_get_last(t.o))
+}
+
+// GetLastS returns the time of the latest onset detected, in seconds.
+//     t.Do(buf)
+//     if t.OnsetNow() {
+//         fmt.Println("Onset at: ", t.GetLastS())
+//     }
+func (t *Onset) GetLastS() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_last_s(t.o))
+}
+
+// GetLastMs returns the time of the latest onset detected, in milliseconds.
+func (t *Onset) GetLastMs() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_onset_get_last_ms(t.o))
 }
-*/
 
 // Free frees the aubio_temp_t object's memory.
 func (t *Onset) Free() {
@@ -0,0 +1,249 @@
+package aubio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/LedFx/aubio-go/eval"
+)
+
+// OnsetConfig holds the parameters that tune an Onset detector.
+type OnsetConfig struct {
+	Threshold float64
+	Silence   float64
+	MinioiMs  float64
+}
+
+// Apply sets the parameters held in c on an Onset.
+func (c OnsetConfig) Apply(o *Onset) {
+	o.SetThreshold(c.Threshold)
+	o.SetSilence(c.Silence)
+	o.SetMinioiMs(c.MinioiMs)
+}
+
+var (
+	defaultCalibrationThresholds = []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.7, 1.0}
+	defaultCalibrationSilences   = []float64{-90, -70, -50, -40}
+	defaultCalibrationMiniois    = []float64{20, 50, 100}
+)
+
+// OnsetCalibration describes the audio and target used by
+// CalibrateOnset to search for the best OnsetConfig.
+type OnsetCalibration struct {
+	Method     OnsetMethod
+	BufSize    uint
+	HopSize    uint
+	Samplerate uint
+
+	// Density is the target number of onsets per second.
+	// It is ignored when Onsets is set.
+	Density float64
+	// Onsets are labeled onset times in seconds.
+	Onsets []float64
+	// Tolerance is the window in seconds within which a detected onset
+	// matches a labeled one. Defaults to 50ms.
+	Tolerance float64
+
+	// Candidate values to search. Sensible defaults are used for any
+	// left empty.
+	Thresholds []float64
+	Silences   []float64
+	MinioisMs  []float64
+}
+
+// OnsetCalibrationResult is the outcome of an onset calibration.
+type OnsetCalibrationResult struct {
+	Config OnsetConfig
+	// Score is the F-measure against the labeled onsets, or one minus
+	// the relative error against the target density. Higher is better.
+	Score float64
+	// Detected is the number of onsets found using Config.
+	Detected int
+}
+
+// CalibrateOnset searches threshold, silence and minioi for the
+// OnsetConfig that best matches the target described by c on frames
+// of HopSize samples. Shorter frames are padded with zeros.
+func CalibrateOnset(frames [][]float64, c OnsetCalibration) (OnsetCalibrationResult, error) {
+	if len(frames) == 0 {
+		return OnsetCalibrationResult{}, errors.New("no frames to calibrate onsets on")
+	}
+	for i, f := range frames {
+		if len(f) > int(c.HopSize) {
+			return OnsetCalibrationResult{}, fmt.Errorf("frame %d has %d samples, want at most %d", i, len(f), c.HopSize)
+		}
+	}
+	if c.Density <= 0 && len(c.Onsets) == 0 {
+		return OnsetCalibrationResult{}, errors.New("onset calibration needs a target density or labeled onsets")
+	}
+	if c.Tolerance == 0 {
+		c.Tolerance = 0.05
+	}
+	thresholds := orDefault(c.Thresholds, defaultCalibrationThresholds)
+	silences := orDefault(c.Silences, defaultCalibrationSilences)
+	miniois := orDefault(c.MinioisMs, defaultCalibrationMiniois)
+	duration := float64(len(frames)) * float64(c.HopSize) / float64(c.Samplerate)
+
+	best := OnsetCalibrationResult{Score: math.Inf(-1)}
+	for _, threshold := range thresholds {
+		for _, silence := range silences {
+			for _, minioi := range miniois {
+				cfg := OnsetConfig{Threshold: threshold, Silence: silence, MinioiMs: minioi}
+				onsets, err := detectOnsets(frames, c, cfg)
+				if err != nil {
+					return OnsetCalibrationResult{}, err
+				}
+				var score float64
+				if len(c.Onsets) > 0 {
+					score = eval.Match(onsets, c.Onsets, c.Tolerance).FMeasure
+				} else {
+					density := float64(len(onsets)) / duration
+					score = 1 - math.Abs(density-c.Density)/c.Density
+				}
+				if score > best.Score {
+					best = OnsetCalibrationResult{Config: cfg, Score: score, Detected: len(onsets)}
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+// CalibrateOnsetSource reads the whole of src and calibrates on it
+// with CalibrateOnset. HopSize and Samplerate default to the ones
+// used by src.
+func CalibrateOnsetSource(src *Source, c OnsetCalibration) (OnsetCalibrationResult, error) {
+	if c.HopSize == 0 {
+		c.HopSize = src.BlockSize()
+	}
+	if c.Samplerate == 0 {
+		c.Samplerate = src.Samplerate()
+	}
+	buf := NewSimpleBuffer(src.BlockSize())
+	defer buf.Free()
+	var samples []float64
+	for {
+		n := src.Do(buf)
+		samples = append(samples, buf.Slice()[:n]...)
+		if n < src.BlockSize() {
+			break
+		}
+	}
+	return CalibrateOnset(splitFrames(samples, int(c.HopSize)), c)
+}
+
+// splitFrames cuts samples into frames of size samples, the last one
+// possibly shorter.
+func splitFrames(samples []float64, size int) [][]float64 {
+	var frames [][]float64
+	for i := 0; i < len(samples); i += size {
+		frames = append(frames, samples[i:minInt(i+size, len(samples))])
+	}
+	return frames
+}
+
+func detectOnsets(frames [][]float64, c OnsetCalibration, cfg OnsetConfig) ([]float64, error) {
+	o, err := NewOnset(c.Method, c.BufSize, c.HopSize, c.Samplerate)
+	if err != nil {
+		return nil, err
+	}
+	defer o.Free()
+	cfg.Apply(o)
+	buf := NewSimpleBuffer(c.HopSize)
+	defer buf.Free()
+	frame := make([]float64, c.HopSize)
+	var onsets []float64
+	for _, f := range frames {
+		n := copy(frame, f)
+		for i := n; i < len(frame); i++ {
+			frame[i] = 0
+		}
+		buf.SetData(frame)
+		o.Do(buf)
+		if o.OnsetNow() {
+			onsets = append(onsets, o.GetLastS())
+		}
+	}
+	return onsets, nil
+}
+
+func orDefault(values, defaults []float64) []float64 {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
+
+// OnsetAutoGain adjusts the threshold of an Onset slowly during live
+// use so that it detects onsets at roughly a target density.
+type OnsetAutoGain struct {
+	onset     *Onset
+	density   float64
+	hop       float64
+	rate      float64
+	threshold float64
+
+	// Window is the time in seconds over which the onset rate is
+	// averaged. Defaults to 8s.
+	Window float64
+	// Adapt is how fast the threshold follows the ratio of the onset
+	// rate to the target density: every second, the threshold is
+	// multiplied by that ratio raised to the power of Adapt.
+	// Defaults to 0.05.
+	Adapt float64
+	// MinThreshold and MaxThreshold bound the threshold.
+	// They default to 0.01 and 2.
+	MinThreshold float64
+	MaxThreshold float64
+}
+
+// NewOnsetAutoGain wraps o so that its threshold tracks the target
+// density, in onsets per second. hopSize and samplerate must be the
+// ones o was constructed with.
+// The caller keeps ownership of o.
+func NewOnsetAutoGain(o *Onset, density float64, hopSize, samplerate uint) (*OnsetAutoGain, error) {
+	if density <= 0 {
+		return nil, fmt.Errorf("invalid onset density %v, must be positive", density)
+	}
+	if hopSize == 0 || samplerate == 0 {
+		return nil, errors.New("onset auto gain needs a hop size and a samplerate")
+	}
+	return &OnsetAutoGain{
+		onset:        o,
+		density:      density,
+		hop:          float64(hopSize) / float64(samplerate),
+		rate:         density,
+		threshold:    o.GetThreshold(),
+		Window:       8,
+		Adapt:        0.05,
+		MinThreshold: 0.01,
+		MaxThreshold: 2,
+	}, nil
+}
+
+// Onset returns the wrapped Onset.
+func (a *OnsetAutoGain) Onset() *Onset {
+	return a.onset
+}
+
+// Rate returns the current smoothed onset rate in onsets per second.
+func (a *OnsetAutoGain) Rate() float64 {
+	return a.rate
+}
+
+// Do runs the wrapped Onset on input and nudges its threshold
+// towards the target density.
+func (a *OnsetAutoGain) Do(input *SimpleBuffer) {
+	a.onset.Do(input)
+	var hit float64
+	if a.onset.OnsetNow() {
+		hit = 1 / a.hop
+	}
+	a.rate += math.Min(a.hop/a.Window, 1) * (hit - a.rate)
+	// too many onsets raise the threshold, too few lower it.
+	ratio := (a.rate + 1e-3) / a.density
+	a.threshold *= math.Pow(ratio, a.Adapt*a.hop)
+	a.threshold = math.Max(a.MinThreshold, math.Min(a.MaxThreshold, a.threshold))
+	a.onset.SetThreshold(a.threshold)
+}
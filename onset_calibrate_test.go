@@ -0,0 +1,110 @@
+package aubio
+
+import (
+	"testing"
+)
+
+func TestSplitFrames(t *testing.T) {
+	samples := make([]float64, 10)
+	for i := range samples {
+		samples[i] = float64(i)
+	}
+	frames := splitFrames(samples, 4)
+	if len(frames) != 3 || len(frames[2]) != 2 || frames[1][0] != 4 || frames[2][1] != 9 {
+		t.Errorf("splitFrames = %v", frames)
+	}
+}
+
+func TestCalibrateOnsetErrors(t *testing.T) {
+	c := OnsetCalibration{Method: HFC, BufSize: testBufSize, HopSize: testHopSize, Samplerate: testSamplerate}
+	if _, err := CalibrateOnset(nil, OnsetCalibration{Density: 2}); err == nil {
+		t.Error("expected an error without frames")
+	}
+	if _, err := CalibrateOnset([][]float64{make([]float64, testHopSize)}, c); err == nil {
+		t.Error("expected an error without a target")
+	}
+	c.Density = 2
+	if _, err := CalibrateOnset([][]float64{make([]float64, testHopSize+1)}, c); err == nil {
+		t.Error("expected an error for a frame longer than the hop")
+	}
+}
+
+func TestCalibrateOnset(t *testing.T) {
+	signal := clickTrack(120, testSamplerate, 5*testSamplerate)
+	frames := splitFrames(signal, testHopSize)
+	c := OnsetCalibration{
+		Method:     HFC,
+		BufSize:    testBufSize,
+		HopSize:    testHopSize,
+		Samplerate: testSamplerate,
+		Onsets:     clickTimes(120, 0, 5),
+	}
+	res, err := CalibrateOnset(frames, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Score < 0.9 {
+		t.Errorf("F-measure %v with %+v, want at least 0.9", res.Score, res.Config)
+	}
+}
+
+func TestOnsetAutoGain(t *testing.T) {
+	o, err := NewOnset(HFC, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Free()
+	if _, err := NewOnsetAutoGain(o, 0, testHopSize, testSamplerate); err == nil {
+		t.Error("expected an error for a zero density")
+	}
+	a, err := NewOnsetAutoGain(o, 2, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// silence has no onsets, so the threshold keeps falling
+	start := o.GetThreshold()
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	for i := 0; i < 10*testSamplerate/testHopSize; i++ {
+		a.Do(buf)
+	}
+	if got := o.GetThreshold(); got >= start || got < a.MinThreshold {
+		t.Errorf("threshold went from %v to %v, want lower but at least %v", start, got, a.MinThreshold)
+	}
+	if a.Rate() >= 2 {
+		t.Errorf("rate %v, want below the target of 2", a.Rate())
+	}
+}
+
+func TestCalibrateOnsetSource(t *testing.T) {
+	// a length that is not a multiple of the hop leaves a short last frame
+	signal := clickTrack(120, testSamplerate, 3*testSamplerate+100)
+	path := t.TempDir() + "/clicks.wav"
+	sink, err := OpenSink(path, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := NewSimpleBuffer(testHopSize)
+	for i := 0; i < len(signal); i += testHopSize {
+		frame := make([]float64, testHopSize)
+		n := copy(frame, signal[i:])
+		buf.SetData(frame)
+		sink.Do(buf, uint(n))
+	}
+	buf.Free()
+	sink.Close()
+
+	src, err := OpenSource(path, testSamplerate, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	c := OnsetCalibration{Method: HFC, BufSize: testBufSize, Onsets: clickTimes(120, 0, 3)}
+	res, err := CalibrateOnsetSource(src, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Score < 0.9 {
+		t.Errorf("F-measure %v with %+v, want at least 0.9", res.Score, res.Config)
+	}
+}
@@ -0,0 +1,147 @@
+package aubio
+
+/*
+#cgo LDFLAGS: -laubio
+#define AUBIO_UNSTABLE 1
+#include <aubio/aubio.h>
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// peakPickerDelay is the number of frames between a novelty value
+// being pushed into the peak picker and the peak picker reporting
+// it as a peak: one frame for the post window and two for the
+// three sample peek window.
+const peakPickerDelay = 3
+
+// PeakPicker is a wrapper for the aubio_peakpicker_t object.
+// It applies aubio's adaptive median threshold to a novelty curve
+// one value at a time, the same way Onset does with its own
+// detection function.
+type PeakPicker struct {
+	o   *C.aubio_peakpicker_t
+	in  *SimpleBuffer
+	buf *SimpleBuffer
+}
+
+// NewPeakPicker constructs a new PeakPicker object.
+// It is the Callers responsibility to call Free on the returned
+// PeakPicker object or leak memory.
+//     pp, err := NewPeakPicker()
+//     if err != nil {
+//         // handle error
+//     }
+//     defer pp.Free()
+func NewPeakPicker() (*PeakPicker, error) {
+	pp, err := C.new_aubio_peakpicker()
+	if pp == nil {
+		return nil, fmt.Errorf("failure creating PeakPicker object %q", err)
+	}
+	return &PeakPicker{o: pp, in: NewSimpleBuffer(1), buf: NewSimpleBuffer(1)}, nil
+}
+
+// Buffer returns the output buffer for this PeakPicker.
+// Its single sample is non zero when a peak was found, and holds the
+// interpolated position of the peak within the three sample peek window.
+func (pp *PeakPicker) Buffer() *SimpleBuffer {
+	return pp.buf
+}
+
+// Do pushes the first sample of input, a novelty value, into the
+// peak picker.
+func (pp *PeakPicker) Do(input *SimpleBuffer) {
+	if pp.o == nil {
+		return
+	}
+	C.aubio_peakpicker_do(pp.o, input.vec, pp.buf.vec)
+}
+
+// DoValue pushes a single novelty value into the peak picker.
+func (pp *PeakPicker) DoValue(novelty float64) {
+	if pp.o == nil {
+		return
+	}
+	C.fvec_set_sample(pp.in.vec, C.smpl_t(novelty), 0)
+	C.aubio_peakpicker_do(pp.o, pp.in.vec, pp.buf.vec)
+}
+
+// PeakNow returns if a peak was detected by the most recent call
+// to Do or DoValue.
+func (pp *PeakPicker) PeakNow() bool {
+	if pp.buf == nil {
+		return false
+	}
+	return C.fvec_get_sample(pp.buf.vec, 0) != 0
+}
+
+// Thresholded returns the most recent novelty value after the
+// adaptive threshold has been subtracted.
+func (pp *PeakPicker) Thresholded() float64 {
+	if pp.o == nil {
+		return 0
+	}
+	return float64(C.fvec_get_sample(C.aubio_peakpicker_get_thresholded_input(pp.o), 0))
+}
+
+// SetThreshold sets the peak picking threshold.
+func (pp *PeakPicker) SetThreshold(threshold float64) {
+	if pp.o == nil {
+		return
+	}
+	C.aubio_peakpicker_set_threshold(pp.o, C.smpl_t(threshold))
+}
+
+// GetThreshold returns the peak picking threshold.
+func (pp *PeakPicker) GetThreshold() float64 {
+	if pp.o == nil {
+		return 0
+	}
+	return float64(C.aubio_peakpicker_get_threshold(pp.o))
+}
+
+// Free frees the aubio_peakpicker_t object's memory.
+func (pp *PeakPicker) Free() {
+	if pp.o != nil {
+		C.del_aubio_peakpicker(pp.o)
+		pp.o = nil
+	}
+	if pp.in != nil {
+		pp.in.Free()
+		pp.in = nil
+	}
+	if pp.buf != nil {
+		pp.buf.Free()
+		pp.buf = nil
+	}
+}
+
+// PickPeaks runs a new PeakPicker over a whole novelty curve and
+// returns the positions of the peaks found, as fractional indexes
+// into curve.
+func PickPeaks(curve []float64, threshold float64) ([]float64, error) {
+	pp, err := NewPeakPicker()
+	if err != nil {
+		return nil, err
+	}
+	defer pp.Free()
+	pp.SetThreshold(threshold)
+	var peaks []float64
+	// pad the curve so peaks in its last frames are still reported.
+	for i := 0; i < len(curve)+peakPickerDelay-1; i++ {
+		v := 0.0
+		if i < len(curve) {
+			v = curve[i]
+		}
+		pp.DoValue(v)
+		if pp.PeakNow() {
+			pos := float64(i-peakPickerDelay) + pp.Buffer().Get(0)
+			if pos >= 0 && pos < float64(len(curve)) {
+				peaks = append(peaks, pos)
+			}
+		}
+	}
+	return peaks, nil
+}
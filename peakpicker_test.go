@@ -0,0 +1,39 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPickPeaks(t *testing.T) {
+	want := []int{20, 50, 81, 99}
+	curve := make([]float64, 100)
+	for _, p := range want {
+		curve[p] = 1
+	}
+	peaks, err := PickPeaks(curve, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peaks) != len(want) {
+		t.Fatalf("got peaks %v, want %v", peaks, want)
+	}
+	for i, p := range peaks {
+		if math.Abs(p-float64(want[i])) > 0.5 {
+			t.Errorf("peak %d at %v, want %d", i, p, want[i])
+		}
+	}
+}
+
+func TestPeakPickerFree(t *testing.T) {
+	pp, err := NewPeakPicker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp.Free()
+	// must not crash once freed
+	pp.DoValue(1)
+	if pp.PeakNow() {
+		t.Error("expected no peak once freed")
+	}
+}
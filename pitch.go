@@ -17,56 +17,54 @@ package aubio
 import "C"
 
 import (
+	"fmt"
 	"log"
 )
 
-type pitchMode string
-
-const (
-	// Pitch detection function
-	// see: https://github.com/piem/aubio/blob/develop/src/pitch/pitch.c
-	PitchDefault pitchMode = "default"
-	PitchYin     pitchMode = "yin"
-	PitchMcomb   pitchMode = "mcomb"
-	PitchSchmitt pitchMode = "schmitt"
-	PitchFcomb   pitchMode = "fcomb"
-	PitchYinfft  pitchMode = "default"
-)
-
-type pitchOutMode string
-
-const (
-	// Pitch detection output modes
-	// see: https://github.com/piem/aubio/blob/develop/src/pitch/pitch.c
-	PitchOutFreq    = "freq"
-	PitchOutMidi    = "midi"
-	PitchOutCent    = "cent"
-	PitchOutBin     = "bin"
-	PitchOutDefault = "default"
-)
-
 // Pitch is a wrapper for the aubio_pitch_t pitch detection object.
 type Pitch struct {
-	o   *C.aubio_pitch_t
-	buf *SimpleBuffer
+	o    *C.aubio_pitch_t
+	buf  *SimpleBuffer
+	unit PitchUnit
 }
 
 // TODO(jwall): Shared buffers?
 
+// PitchOrDie constructs a new Pitch object.
+// It panics on any errors.
+func PitchOrDie(mode PitchMethod, bufSize, blockSize, sampleRate uint) *Pitch {
+	if p, err := NewPitch(mode, bufSize, blockSize, sampleRate); err == nil {
+		return p
+	} else {
+		panic(err)
+	}
+}
+
 // NewPitch constructs a new Pitch object.
 // It is the Callers responsibility to call Free on the returned
 // Pitch object or leak memory.
-//     p := NewPitch(mode, bufSize, blockSize, samplerate)
+//     p, err := NewPitch(mode, bufSize, blockSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
 //     defer p.Free()
-func NewPitch(mode pitchMode, bufSize, blockSize, sampleRate uint) *Pitch {
-	return &Pitch{
-		o: C.new_aubio_pitch(
-			toCharTPtr(string(mode)),
-			C.uint_t(bufSize),
-			C.uint_t(blockSize),
-			C.uint_t(sampleRate)),
-		buf: NewSimpleBuffer(blockSize),
+func NewPitch(mode PitchMethod, bufSize, blockSize, sampleRate uint) (*Pitch, error) {
+	if err := mode.validate(); err != nil {
+		return nil, err
 	}
+	p, err := C.new_aubio_pitch(
+		toCharTPtr(string(mode)),
+		C.uint_t(bufSize),
+		C.uint_t(blockSize),
+		C.uint_t(sampleRate))
+	if p == nil {
+		return nil, fmt.Errorf("failure creating Pitch object %q", err)
+	}
+	return &Pitch{
+		o:    p,
+		buf:  NewSimpleBuffer(blockSize),
+		unit: PitchOutDefault,
+	}, nil
 }
 
 func (p *Pitch) Buffer() *SimpleBuffer {
@@ -75,21 +73,70 @@ func (p *Pitch) Buffer() *SimpleBuffer {
 
 // SetTolerance sets the yin or yinfft tolerance threshold.
 func (p *Pitch) SetTolerance(tol float64) {
+	if p.o == nil {
+		return
+	}
 	C.aubio_pitch_set_tolerance(p.o, C.smpl_t(tol))
 }
 
+// GetTolerance returns the yin or yinfft tolerance threshold.
+func (p *Pitch) GetTolerance() float64 {
+	if p.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitch_get_tolerance(p.o))
+}
+
 // SetUnit sets the output unit.
-func (p *Pitch) SetUnit(outMode pitchOutMode) {
-	C.aubio_pitch_set_unit(p.o, toCharTPtr(string(outMode)))
+// It returns an error if outMode is not a known PitchUnit.
+func (p *Pitch) SetUnit(outMode PitchUnit) error {
+	if err := outMode.validate(); err != nil {
+		return err
+	}
+	if p.o == nil {
+		return fmt.Errorf("failure setting Pitch unit %q on freed Pitch", outMode)
+	}
+	if C.aubio_pitch_set_unit(p.o, toCharTPtr(string(outMode))) != 0 {
+		return fmt.Errorf("failure setting Pitch unit %q", outMode)
+	}
+	p.unit = outMode
+	return nil
+}
+
+// GetUnit returns the output unit.
+func (p *Pitch) GetUnit() PitchUnit {
+	return p.unit
+}
+
+// SetSilence sets the silence threshold in dB. Frames quieter than
+// the threshold are reported with a pitch of 0.
+func (p *Pitch) SetSilence(silence float64) {
+	if p.o == nil {
+		return
+	}
+	C.aubio_pitch_set_silence(p.o, C.smpl_t(silence))
 }
 
-/* TODO(jwall) Update to latest version of aubio and add this in
-// GetConfidence returns the current confidence of the pitch detection
-// algorithm.
+// GetSilence returns the silence threshold in dB.
+func (p *Pitch) GetSilence() float64 {
+	if p.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitch_get_silence(p.o))
+}
+
+// GetConfidence returns the confidence of the most recent pitch
+// estimate. Not all methods provide one, those that don't return 0.
+//     p.Do(buf)
+//     if p.GetConfidence() > 0.8 {
+//         fmt.Println("Pitch: ", p.Buffer().Get(0))
+//     }
 func (p *Pitch) GetConfidence() float64 {
+	if p.o == nil {
+		return 0
+	}
 	return float64(C.aubio_pitch_get_confidence(p.o))
 }
-*/
 
 // Do runs one step of the pitch detection as determined by the bufSize.
 func (p *Pitch) Do(in *SimpleBuffer) {
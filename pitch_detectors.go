@@ -0,0 +1,494 @@
+package aubio
+
+/*
+#cgo LDFLAGS: -laubio
+#define AUBIO_UNSTABLE 1
+#include <aubio/aubio.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"log"
+)
+
+// The per algorithm pitch detectors below are the building blocks
+// Pitch selects between. They let each algorithm be tuned and
+// benchmarked on its own. Except for MComb they take a full bufSize
+// window of samples on each call to Do, rather than a hop, and they
+// report their raw output in Buffer: a period in samples for the yin
+// family, specacf and schmitt, an FFT bin for fcomb and mcomb.
+// Frequency converts the latest estimate to Hz.
+
+// periodToFreq converts a period in samples to a frequency in Hz.
+func periodToFreq(period float64, samplerate uint) float64 {
+	if period <= 0 {
+		return 0
+	}
+	return float64(samplerate) / period
+}
+
+// Yin is a wrapper for the aubio_pitchyin_t pitch detection object.
+type Yin struct {
+	o          *C.aubio_pitchyin_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewYin constructs a new Yin object.
+// It is the Callers responsibility to call Free on the returned
+// Yin object or leak memory.
+func NewYin(bufSize, samplerate uint) (*Yin, error) {
+	o, err := C.new_aubio_pitchyin(C.uint_t(bufSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating Yin object %q", err)
+	}
+	return &Yin{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (y *Yin) Buffer() *SimpleBuffer {
+	return y.buf
+}
+
+// Do estimates the period of a bufSize window of samples.
+func (y *Yin) Do(in *SimpleBuffer) {
+	if y.o == nil {
+		log.Println("Called Do on empty Yin. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != y.bufSize {
+		log.Printf("Called Do on Yin with a buffer of %d samples, want %d", in.Size(), y.bufSize)
+		return
+	}
+	C.aubio_pitchyin_do(y.o, in.vec, y.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (y *Yin) Frequency() float64 {
+	return periodToFreq(y.buf.Get(0), y.samplerate)
+}
+
+// SetTolerance sets the yin tolerance threshold.
+func (y *Yin) SetTolerance(tol float64) {
+	if y.o != nil {
+		C.aubio_pitchyin_set_tolerance(y.o, C.smpl_t(tol))
+	}
+}
+
+// GetTolerance returns the yin tolerance threshold.
+func (y *Yin) GetTolerance() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyin_get_tolerance(y.o))
+}
+
+// GetConfidence returns the confidence of the latest estimate.
+func (y *Yin) GetConfidence() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyin_get_confidence(y.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (y *Yin) Free() {
+	if y.o != nil {
+		C.del_aubio_pitchyin(y.o)
+		y.o = nil
+	}
+	if y.buf != nil {
+		y.buf.Free()
+		y.buf = nil
+	}
+}
+
+// YinFFT is a wrapper for the aubio_pitchyinfft_t pitch detection object.
+type YinFFT struct {
+	o          *C.aubio_pitchyinfft_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewYinFFT constructs a new YinFFT object.
+// It is the Callers responsibility to call Free on the returned
+// YinFFT object or leak memory.
+func NewYinFFT(bufSize, samplerate uint) (*YinFFT, error) {
+	o, err := C.new_aubio_pitchyinfft(C.uint_t(samplerate), C.uint_t(bufSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating YinFFT object %q", err)
+	}
+	return &YinFFT{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (y *YinFFT) Buffer() *SimpleBuffer {
+	return y.buf
+}
+
+// Do estimates the period of a bufSize window of samples.
+func (y *YinFFT) Do(in *SimpleBuffer) {
+	if y.o == nil {
+		log.Println("Called Do on empty YinFFT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != y.bufSize {
+		log.Printf("Called Do on YinFFT with a buffer of %d samples, want %d", in.Size(), y.bufSize)
+		return
+	}
+	C.aubio_pitchyinfft_do(y.o, in.vec, y.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (y *YinFFT) Frequency() float64 {
+	return periodToFreq(y.buf.Get(0), y.samplerate)
+}
+
+// SetTolerance sets the yinfft tolerance threshold.
+func (y *YinFFT) SetTolerance(tol float64) {
+	if y.o != nil {
+		C.aubio_pitchyinfft_set_tolerance(y.o, C.smpl_t(tol))
+	}
+}
+
+// GetTolerance returns the yinfft tolerance threshold.
+func (y *YinFFT) GetTolerance() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyinfft_get_tolerance(y.o))
+}
+
+// GetConfidence returns the confidence of the latest estimate.
+func (y *YinFFT) GetConfidence() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyinfft_get_confidence(y.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (y *YinFFT) Free() {
+	if y.o != nil {
+		C.del_aubio_pitchyinfft(y.o)
+		y.o = nil
+	}
+	if y.buf != nil {
+		y.buf.Free()
+		y.buf = nil
+	}
+}
+
+// YinFast is a wrapper for the aubio_pitchyinfast_t pitch detection object.
+type YinFast struct {
+	o          *C.aubio_pitchyinfast_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewYinFast constructs a new YinFast object.
+// It is the Callers responsibility to call Free on the returned
+// YinFast object or leak memory.
+func NewYinFast(bufSize, samplerate uint) (*YinFast, error) {
+	o, err := C.new_aubio_pitchyinfast(C.uint_t(bufSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating YinFast object %q", err)
+	}
+	return &YinFast{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (y *YinFast) Buffer() *SimpleBuffer {
+	return y.buf
+}
+
+// Do estimates the period of a bufSize window of samples.
+func (y *YinFast) Do(in *SimpleBuffer) {
+	if y.o == nil {
+		log.Println("Called Do on empty YinFast. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != y.bufSize {
+		log.Printf("Called Do on YinFast with a buffer of %d samples, want %d", in.Size(), y.bufSize)
+		return
+	}
+	C.aubio_pitchyinfast_do(y.o, in.vec, y.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (y *YinFast) Frequency() float64 {
+	return periodToFreq(y.buf.Get(0), y.samplerate)
+}
+
+// SetTolerance sets the yinfast tolerance threshold.
+func (y *YinFast) SetTolerance(tol float64) {
+	if y.o != nil {
+		C.aubio_pitchyinfast_set_tolerance(y.o, C.smpl_t(tol))
+	}
+}
+
+// GetTolerance returns the yinfast tolerance threshold.
+func (y *YinFast) GetTolerance() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyinfast_get_tolerance(y.o))
+}
+
+// GetConfidence returns the confidence of the latest estimate.
+func (y *YinFast) GetConfidence() float64 {
+	if y.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchyinfast_get_confidence(y.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (y *YinFast) Free() {
+	if y.o != nil {
+		C.del_aubio_pitchyinfast(y.o)
+		y.o = nil
+	}
+	if y.buf != nil {
+		y.buf.Free()
+		y.buf = nil
+	}
+}
+
+// SpecACF is a wrapper for the aubio_pitchspecacf_t pitch detection object.
+type SpecACF struct {
+	o          *C.aubio_pitchspecacf_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewSpecACF constructs a new SpecACF object.
+// It is the Callers responsibility to call Free on the returned
+// SpecACF object or leak memory.
+func NewSpecACF(bufSize, samplerate uint) (*SpecACF, error) {
+	o, err := C.new_aubio_pitchspecacf(C.uint_t(bufSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating SpecACF object %q", err)
+	}
+	return &SpecACF{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (s *SpecACF) Buffer() *SimpleBuffer {
+	return s.buf
+}
+
+// Do estimates the period of a bufSize window of samples.
+func (s *SpecACF) Do(in *SimpleBuffer) {
+	if s.o == nil {
+		log.Println("Called Do on empty SpecACF. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != s.bufSize {
+		log.Printf("Called Do on SpecACF with a buffer of %d samples, want %d", in.Size(), s.bufSize)
+		return
+	}
+	C.aubio_pitchspecacf_do(s.o, in.vec, s.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (s *SpecACF) Frequency() float64 {
+	return periodToFreq(s.buf.Get(0), s.samplerate)
+}
+
+// SetTolerance sets the specacf tolerance threshold.
+func (s *SpecACF) SetTolerance(tol float64) {
+	if s.o != nil {
+		C.aubio_pitchspecacf_set_tolerance(s.o, C.smpl_t(tol))
+	}
+}
+
+// GetTolerance returns the specacf tolerance threshold.
+func (s *SpecACF) GetTolerance() float64 {
+	if s.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchspecacf_get_tolerance(s.o))
+}
+
+// GetConfidence returns the confidence of the latest estimate.
+func (s *SpecACF) GetConfidence() float64 {
+	if s.o == nil {
+		return 0
+	}
+	return float64(C.aubio_pitchspecacf_get_confidence(s.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (s *SpecACF) Free() {
+	if s.o != nil {
+		C.del_aubio_pitchspecacf(s.o)
+		s.o = nil
+	}
+	if s.buf != nil {
+		s.buf.Free()
+		s.buf = nil
+	}
+}
+
+// Schmitt is a wrapper for the aubio_pitchschmitt_t pitch detection object.
+type Schmitt struct {
+	o          *C.aubio_pitchschmitt_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewSchmitt constructs a new Schmitt object.
+// It is the Callers responsibility to call Free on the returned
+// Schmitt object or leak memory.
+func NewSchmitt(bufSize, samplerate uint) (*Schmitt, error) {
+	o, err := C.new_aubio_pitchschmitt(C.uint_t(bufSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating Schmitt object %q", err)
+	}
+	return &Schmitt{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (s *Schmitt) Buffer() *SimpleBuffer {
+	return s.buf
+}
+
+// Do estimates the period of a bufSize window of samples.
+func (s *Schmitt) Do(in *SimpleBuffer) {
+	if s.o == nil {
+		log.Println("Called Do on empty Schmitt. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != s.bufSize {
+		log.Printf("Called Do on Schmitt with a buffer of %d samples, want %d", in.Size(), s.bufSize)
+		return
+	}
+	C.aubio_pitchschmitt_do(s.o, in.vec, s.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (s *Schmitt) Frequency() float64 {
+	return periodToFreq(s.buf.Get(0), s.samplerate)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (s *Schmitt) Free() {
+	if s.o != nil {
+		C.del_aubio_pitchschmitt(s.o)
+		s.o = nil
+	}
+	if s.buf != nil {
+		s.buf.Free()
+		s.buf = nil
+	}
+}
+
+// FComb is a wrapper for the aubio_pitchfcomb_t pitch detection object.
+type FComb struct {
+	o          *C.aubio_pitchfcomb_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewFComb constructs a new FComb object.
+// It is the Callers responsibility to call Free on the returned
+// FComb object or leak memory.
+func NewFComb(bufSize, hopSize, samplerate uint) (*FComb, error) {
+	o, err := C.new_aubio_pitchfcomb(C.uint_t(bufSize), C.uint_t(hopSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating FComb object %q", err)
+	}
+	return &FComb{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (f *FComb) Buffer() *SimpleBuffer {
+	return f.buf
+}
+
+// Do estimates the fundamental bin of a bufSize window of samples.
+func (f *FComb) Do(in *SimpleBuffer) {
+	if f.o == nil {
+		log.Println("Called Do on empty FComb. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != f.bufSize {
+		log.Printf("Called Do on FComb with a buffer of %d samples, want %d", in.Size(), f.bufSize)
+		return
+	}
+	C.aubio_pitchfcomb_do(f.o, in.vec, f.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (f *FComb) Frequency() float64 {
+	return BinToFreq(f.buf.Get(0), f.samplerate, f.bufSize)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (f *FComb) Free() {
+	if f.o != nil {
+		C.del_aubio_pitchfcomb(f.o)
+		f.o = nil
+	}
+	if f.buf != nil {
+		f.buf.Free()
+		f.buf = nil
+	}
+}
+
+// MComb is a wrapper for the aubio_pitchmcomb_t pitch detection object.
+// Unlike the other detectors it works on spectral frames, such as the
+// Grain of a PhaseVoc constructed with the same bufSize and hopSize.
+type MComb struct {
+	o          *C.aubio_pitchmcomb_t
+	buf        *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+}
+
+// NewMComb constructs a new MComb object.
+// It is the Callers responsibility to call Free on the returned
+// MComb object or leak memory.
+func NewMComb(bufSize, hopSize, samplerate uint) (*MComb, error) {
+	o, err := C.new_aubio_pitchmcomb(C.uint_t(bufSize), C.uint_t(hopSize))
+	if o == nil {
+		return nil, fmt.Errorf("failure creating MComb object %q", err)
+	}
+	return &MComb{o: o, buf: NewSimpleBuffer(1), bufSize: bufSize, samplerate: samplerate}, nil
+}
+
+func (m *MComb) Buffer() *SimpleBuffer {
+	return m.buf
+}
+
+// Do estimates the fundamental bin of a spectral frame.
+func (m *MComb) Do(in *ComplexBuffer) {
+	if m.o == nil {
+		log.Println("Called Do on empty MComb. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != m.bufSize/2+1 {
+		log.Printf("Called Do on MComb with a spectrum of %d bins, want %d", in.Size(), m.bufSize/2+1)
+		return
+	}
+	C.aubio_pitchmcomb_do(m.o, in.data, m.buf.vec)
+}
+
+// Frequency returns the latest estimate in Hz.
+func (m *MComb) Frequency() float64 {
+	return BinToFreq(m.buf.Get(0), m.samplerate, m.bufSize)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (m *MComb) Free() {
+	if m.o != nil {
+		C.del_aubio_pitchmcomb(m.o)
+		m.o = nil
+	}
+	if m.buf != nil {
+		m.buf.Free()
+		m.buf = nil
+	}
+}
@@ -0,0 +1,270 @@
+package aubio
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+const (
+	testSamplerate = 44100
+	testBufSize    = 2048
+	testHopSize    = 512
+)
+
+// tone synthesizes n samples of a harmonic tone at freq Hz.
+func tone(freq float64, samplerate, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		t := float64(i) / float64(samplerate)
+		for h, amp := range []float64{0.5, 0.25, 0.125} {
+			out[i] += amp * math.Sin(2*math.Pi*freq*float64(h+1)*t)
+		}
+	}
+	return out
+}
+
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), values...)
+	sort.Float64s(s)
+	return s[len(s)/2]
+}
+
+func TestPitchMethods(t *testing.T) {
+	signal := tone(440, testSamplerate, testSamplerate)
+	for _, method := range AllPitchMethods() {
+		p, err := NewPitch(method, testBufSize, testHopSize, testSamplerate)
+		if err != nil {
+			t.Errorf("%s: %v", method, err)
+			continue
+		}
+		if err := p.SetUnit(PitchOutFreq); err != nil {
+			t.Errorf("%s: %v", method, err)
+		}
+		in := NewSimpleBuffer(testHopSize)
+		var estimates []float64
+		for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+			in.SetData(signal[i : i+testHopSize])
+			p.Do(in)
+			// skip the frames still filling the analysis window
+			if i >= testBufSize {
+				estimates = append(estimates, p.Buffer().Get(0))
+			}
+		}
+		if got := medianOf(estimates); math.Abs(got-440)/440 > 0.03 {
+			t.Errorf("%s: median estimate %.2fHz, want 440Hz", method, got)
+		}
+		in.Free()
+		p.Free()
+	}
+}
+
+func TestPitchRejectsUnknownMethod(t *testing.T) {
+	if _, err := NewPitch("yin-ish", testBufSize, testHopSize, testSamplerate); err == nil {
+		t.Error("expected an error for an unknown pitch method")
+	}
+}
+
+func TestPitchOrDie(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected PitchOrDie to panic on an unknown pitch method")
+		}
+	}()
+	PitchOrDie("yin-ish", testBufSize, testHopSize, testSamplerate)
+}
+
+// runPitch feeds signal to p one hop at a time and returns the pitch
+// and confidence of the last frame.
+func runPitch(p *Pitch, signal []float64) (float64, float64) {
+	in := NewSimpleBuffer(testHopSize)
+	defer in.Free()
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		in.SetData(signal[i : i+testHopSize])
+		p.Do(in)
+	}
+	return p.Buffer().Get(0), p.GetConfidence()
+}
+
+func TestPitchSettings(t *testing.T) {
+	p := PitchOrDie(PitchYinfft, testBufSize, testHopSize, testSamplerate)
+	defer p.Free()
+	p.SetSilence(-40)
+	if got := p.GetSilence(); math.Abs(got+40) > 1e-6 {
+		t.Errorf("GetSilence = %v, want -40", got)
+	}
+	p.SetTolerance(0.3)
+	if got := p.GetTolerance(); math.Abs(got-0.3) > 1e-6 {
+		t.Errorf("GetTolerance = %v, want 0.3", got)
+	}
+	if got := p.GetUnit(); got != PitchOutDefault {
+		t.Errorf("GetUnit = %q, want %q", got, PitchOutDefault)
+	}
+	if err := p.SetUnit(PitchOutMidi); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetUnit("semitones"); err == nil {
+		t.Error("expected an error for an unknown pitch unit")
+	}
+	if got := p.GetUnit(); got != PitchOutMidi {
+		t.Errorf("GetUnit = %q, want %q", got, PitchOutMidi)
+	}
+	// the unit reported is the one aubio applies
+	if got, _ := runPitch(p, tone(440, testSamplerate, testSamplerate/4)); math.Abs(got-69) > 0.5 {
+		t.Errorf("got %v for A4 in %s, want 69", got, p.GetUnit())
+	}
+}
+
+func TestPitchConfidence(t *testing.T) {
+	p := PitchOrDie(PitchYinfft, testBufSize, testHopSize, testSamplerate)
+	defer p.Free()
+	_, conf := runPitch(p, tone(440, testSamplerate, testSamplerate/4))
+	if conf < 0.5 || conf > 1 {
+		t.Errorf("confidence %v on a tone, want close to 1", conf)
+	}
+	_, conf = runPitch(p, make([]float64, testSamplerate/4))
+	if conf < 0 || conf > 0.1 {
+		t.Errorf("confidence %v on silence, want close to 0", conf)
+	}
+	p.Free()
+	if p.GetConfidence() != 0 || p.GetSilence() != 0 || p.GetTolerance() != 0 {
+		t.Error("expected zero values once freed")
+	}
+}
+
+type pitchDetector interface {
+	Do(in *SimpleBuffer)
+	Frequency() float64
+	Free()
+}
+
+func newPitchDetectors(t testing.TB) map[string]pitchDetector {
+	detectors := map[string]pitchDetector{}
+	add := func(name string, d pitchDetector, err error) {
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		detectors[name] = d
+	}
+	yin, err := NewYin(testBufSize, testSamplerate)
+	add("yin", yin, err)
+	yinfft, err := NewYinFFT(testBufSize, testSamplerate)
+	add("yinfft", yinfft, err)
+	yinfast, err := NewYinFast(testBufSize, testSamplerate)
+	add("yinfast", yinfast, err)
+	specacf, err := NewSpecACF(testBufSize, testSamplerate)
+	add("specacf", specacf, err)
+	schmitt, err := NewSchmitt(testBufSize, testSamplerate)
+	add("schmitt", schmitt, err)
+	fcomb, err := NewFComb(testBufSize, testHopSize, testSamplerate)
+	add("fcomb", fcomb, err)
+	mcomb, err := newMCombDetector(testBufSize, testHopSize, testSamplerate)
+	add("mcomb", mcomb, err)
+	return detectors
+}
+
+// mcombDetector feeds a window to MComb one hop at a time through a
+// PhaseVoc, the way Pitch does, so it fits the pitchDetector table.
+// Its cost includes the PhaseVoc.
+type mcombDetector struct {
+	pv  *PhaseVoc
+	m   *MComb
+	hop *SimpleBuffer
+}
+
+func newMCombDetector(bufSize, hopSize, samplerate uint) (*mcombDetector, error) {
+	pv, err := NewPhaseVoc(bufSize, hopSize)
+	if err != nil {
+		return nil, err
+	}
+	m, err := NewMComb(bufSize, hopSize, samplerate)
+	if err != nil {
+		pv.Free()
+		return nil, err
+	}
+	return &mcombDetector{pv: pv, m: m, hop: NewSimpleBuffer(hopSize)}, nil
+}
+
+func (d *mcombDetector) Do(in *SimpleBuffer) {
+	window := in.Slice()
+	hop := int(d.hop.Size())
+	for i := 0; i+hop <= len(window); i += hop {
+		d.hop.SetData(window[i : i+hop])
+		d.pv.Do(d.hop)
+		d.m.Do(d.pv.Grain())
+	}
+}
+
+func (d *mcombDetector) Frequency() float64 {
+	return d.m.Frequency()
+}
+
+func (d *mcombDetector) Free() {
+	d.pv.Free()
+	d.m.Free()
+	d.hop.Free()
+}
+
+func TestPitchDetectors(t *testing.T) {
+	signal := tone(440, testSamplerate, testBufSize)
+	in := NewSimpleBufferData(testBufSize, signal)
+	defer in.Free()
+	for name, d := range newPitchDetectors(t) {
+		// schmitt needs a couple of windows to lock on
+		for i := 0; i < 4; i++ {
+			d.Do(in)
+		}
+		if got := d.Frequency(); math.Abs(got-440)/440 > 0.03 {
+			t.Errorf("%s: estimate %.2fHz, want 440Hz", name, got)
+		}
+		d.Free()
+	}
+}
+
+func TestPitchDetectorsSizeMismatch(t *testing.T) {
+	short := NewSimpleBufferData(testBufSize/2, tone(440, testSamplerate, testBufSize/2))
+	defer short.Free()
+	long := NewSimpleBufferData(2*testBufSize, tone(440, testSamplerate, 2*testBufSize))
+	defer long.Free()
+	for name, d := range newPitchDetectors(t) {
+		// mcombDetector splits its input in hops, MComb is tested below
+		if name != "mcomb" {
+			for i := 0; i < 4; i++ {
+				d.Do(short)
+				d.Do(long)
+			}
+			if got := d.Frequency(); got != 0 {
+				t.Errorf("%s: estimate %.2fHz from mismatched buffers, want none", name, got)
+			}
+		}
+		d.Free()
+	}
+
+	m, err := NewMComb(testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Free()
+	grain := triangleSpectrum(2*testBufSize, 20, 2)
+	defer grain.Free()
+	m.Do(grain)
+	if got := m.Frequency(); got != 0 {
+		t.Errorf("mcomb: estimate %.2fHz from a mismatched spectrum, want none", got)
+	}
+}
+
+func BenchmarkPitchDetectors(b *testing.B) {
+	in := NewSimpleBufferData(testBufSize, tone(440, testSamplerate, testBufSize))
+	defer in.Free()
+	for name, d := range newPitchDetectors(b) {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.Do(in)
+			}
+		})
+		d.Free()
+	}
+}
@@ -0,0 +1,234 @@
+package aubio
+
+import (
+	"log"
+	"math"
+	"sort"
+)
+
+// PitchPoint is a single post-processed pitch estimate.
+type PitchPoint struct {
+	// Time is the start of the hop the estimate was made on, in seconds.
+	Time float64
+	// Hz and Midi are 0 when the frame is unvoiced.
+	Hz         float64
+	Midi       float64
+	Confidence float64
+	Voiced     bool
+}
+
+// PitchFilter selects how PitchTracker smooths voiced estimates.
+type PitchFilter int
+
+const (
+	// PitchFilterMedian outputs the median of the last MedianSize estimates.
+	PitchFilterMedian PitchFilter = iota
+	// PitchFilterHysteresis only moves the output once an estimate
+	// differs from it by more than HysteresisCents.
+	PitchFilterHysteresis
+	// PitchFilterNone outputs the gated and octave corrected estimates.
+	PitchFilterNone
+)
+
+// PitchTracker cleans up the raw output of Pitch. It gates frames on
+// confidence, corrects octave jumps, smooths the estimates and
+// segments them into voiced and unvoiced regions.
+type PitchTracker struct {
+	pitch      *Pitch
+	hopSize    uint
+	samplerate uint
+	frames     uint
+	point      PitchPoint
+
+	history     []float64
+	current     float64
+	voiced      bool
+	switchRun   int
+	octaveRun   int
+	octaveShift float64
+
+	// MinConfidence is the confidence below which a frame is
+	// considered unvoiced. Defaults to 0.8, or to 0 for schmitt, fcomb
+	// and mcomb, which report no confidence.
+	MinConfidence float64
+	// Filter selects the smoothing. Defaults to PitchFilterMedian.
+	Filter PitchFilter
+	// MedianSize is the number of estimates the median filter spans.
+	// Values below 1 are treated as 1. Defaults to 5.
+	MedianSize int
+	// HysteresisCents is the dead band of the hysteresis filter.
+	// Defaults to 50.
+	HysteresisCents float64
+	// OctaveCorrection folds estimates that jump by whole octaves
+	// back onto the current note, unless the jump lasts longer than
+	// OctaveHold frames. Defaults to true and 5.
+	OctaveCorrection bool
+	OctaveHold       int
+	// MinRun is the number of consecutive frames needed to switch
+	// between voiced and unvoiced. Defaults to 3.
+	MinRun int
+}
+
+// NewPitchTracker constructs a new PitchTracker on top of a new Pitch
+// object reporting in Hz.
+// It is the Callers responsibility to call Free on the returned
+// PitchTracker object or leak memory.
+//     pt, err := NewPitchTracker(PitchYinfft, bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer pt.Free()
+func NewPitchTracker(method PitchMethod, bufSize, hopSize, samplerate uint) (*PitchTracker, error) {
+	p, err := NewPitch(method, bufSize, hopSize, samplerate)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetUnit(PitchOutFreq); err != nil {
+		p.Free()
+		return nil, err
+	}
+	minConfidence := 0.8
+	if !method.reportsConfidence() {
+		minConfidence = 0
+	}
+	return &PitchTracker{
+		pitch:            p,
+		hopSize:          hopSize,
+		samplerate:       samplerate,
+		MinConfidence:    minConfidence,
+		Filter:           PitchFilterMedian,
+		MedianSize:       5,
+		HysteresisCents:  50,
+		OctaveCorrection: true,
+		OctaveHold:       5,
+		MinRun:           3,
+	}, nil
+}
+
+// Pitch returns the underlying Pitch object, for instance to tune
+// its tolerance or silence threshold.
+func (pt *PitchTracker) Pitch() *Pitch {
+	return pt.pitch
+}
+
+// Point returns the smoothed estimate of the latest call to Do.
+func (pt *PitchTracker) Point() PitchPoint {
+	return pt.point
+}
+
+// Do runs the pitch detection on a hopSize input Buffer and updates
+// the smoothed estimate.
+func (pt *PitchTracker) Do(input *SimpleBuffer) {
+	if pt.pitch == nil {
+		log.Println("Called Do on empty PitchTracker. Maybe you called Free previously?")
+		return
+	}
+	pt.pitch.Do(input)
+	pt.update(pt.pitch.Buffer().Get(0), pt.pitch.GetConfidence())
+}
+
+// update post-processes a single raw estimate.
+func (pt *PitchTracker) update(hz, confidence float64) {
+	t := float64(pt.frames) / float64(pt.samplerate)
+	pt.frames += pt.hopSize
+
+	candidate := hz > 0 && confidence >= pt.MinConfidence
+	if candidate != pt.voiced {
+		pt.switchRun++
+		if pt.switchRun >= pt.MinRun {
+			pt.voiced = candidate
+			pt.switchRun = 0
+			pt.history = nil
+			pt.octaveRun = 0
+			pt.octaveShift = 0
+		}
+	} else {
+		pt.switchRun = 0
+	}
+
+	pt.point = PitchPoint{Time: t, Confidence: confidence}
+	if !pt.voiced {
+		return
+	}
+	if candidate {
+		midi := pt.correctOctave(FreqToMidi(hz))
+		pt.current = pt.smooth(midi)
+	}
+	pt.point.Voiced = true
+	pt.point.Midi = pt.current
+	pt.point.Hz = MidiToFreq(pt.current)
+}
+
+// correctOctave folds midi onto the octave of the current note.
+func (pt *PitchTracker) correctOctave(midi float64) float64 {
+	if !pt.OctaveCorrection || len(pt.history) == 0 {
+		return midi
+	}
+	octaves := math.Round((midi - pt.current) / 12)
+	if octaves == 0 || math.Abs(midi-pt.current-12*octaves) > 1 {
+		pt.octaveRun = 0
+		return midi
+	}
+	if octaves != pt.octaveShift {
+		pt.octaveShift = octaves
+		pt.octaveRun = 0
+	}
+	pt.octaveRun++
+	if pt.octaveRun > pt.OctaveHold {
+		// the jump has lasted, accept it as a real octave change.
+		pt.history = nil
+		pt.octaveRun = 0
+		return midi
+	}
+	return midi - 12*octaves
+}
+
+func (pt *PitchTracker) smooth(midi float64) float64 {
+	first := len(pt.history) == 0
+	pt.history = append(pt.history, midi)
+	if size := maxInt(pt.MedianSize, 1); len(pt.history) > size {
+		pt.history = pt.history[len(pt.history)-size:]
+	}
+	switch pt.Filter {
+	case PitchFilterMedian:
+		s := append([]float64(nil), pt.history...)
+		sort.Float64s(s)
+		return s[len(s)/2]
+	case PitchFilterHysteresis:
+		if first || math.Abs(midi-pt.current)*100 > pt.HysteresisCents {
+			return midi
+		}
+		return pt.current
+	default:
+		return midi
+	}
+}
+
+// Track runs the PitchTracker over the whole of src and returns a
+// point for each hop. src must use the hopSize the PitchTracker was
+// constructed with.
+func (pt *PitchTracker) Track(src *Source) []PitchPoint {
+	buf := NewSimpleBuffer(pt.hopSize)
+	defer buf.Free()
+	var points []PitchPoint
+	for {
+		n := src.Do(buf)
+		if n == 0 {
+			break
+		}
+		pt.Do(buf)
+		points = append(points, pt.point)
+		if n < pt.hopSize {
+			break
+		}
+	}
+	return points
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (pt *PitchTracker) Free() {
+	if pt.pitch != nil {
+		pt.pitch.Free()
+		pt.pitch = nil
+	}
+}
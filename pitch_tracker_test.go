@@ -0,0 +1,101 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestPitchTracker(t *testing.T) *PitchTracker {
+	pt, err := NewPitchTracker(PitchYinfft, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pt
+}
+
+func TestPitchTrackerOctaveCorrection(t *testing.T) {
+	pt := newTestPitchTracker(t)
+	defer pt.Free()
+	raw := []float64{220, 220, 220, 220, 220, 440, 220, 220, 110, 220, 220}
+	for i, hz := range raw {
+		pt.update(hz, 0.9)
+		p := pt.Point()
+		if i >= pt.MinRun-1 && (!p.Voiced || math.Abs(p.Hz-220) > 0.01) {
+			t.Errorf("frame %d: got %+v, want a steady 220Hz", i, p)
+		}
+	}
+}
+
+func TestPitchTrackerAcceptsLastingOctaveJump(t *testing.T) {
+	pt := newTestPitchTracker(t)
+	defer pt.Free()
+	for i := 0; i < 10; i++ {
+		pt.update(220, 0.9)
+	}
+	for i := 0; i < 20; i++ {
+		pt.update(440, 0.9)
+	}
+	if p := pt.Point(); math.Abs(p.Hz-440) > 0.01 {
+		t.Errorf("got %+v, want 440Hz after a lasting octave jump", p)
+	}
+}
+
+func TestPitchTrackerSegmentation(t *testing.T) {
+	pt := newTestPitchTracker(t)
+	defer pt.Free()
+	var voiced []bool
+	frames := []struct{ hz, confidence float64 }{
+		{0, 0}, {0, 0}, {330, 0.9}, {330, 0.9}, {330, 0.9}, {330, 0.9},
+		{330, 0.2}, {330, 0.9}, {0, 0}, {0, 0}, {0, 0}, {0, 0},
+	}
+	for _, f := range frames {
+		pt.update(f.hz, f.confidence)
+		voiced = append(voiced, pt.Point().Voiced)
+	}
+	want := []bool{false, false, false, false, true, true, true, true, true, true, false, false}
+	for i := range want {
+		if voiced[i] != want[i] {
+			t.Errorf("voiced got %v, want %v", voiced, want)
+			break
+		}
+	}
+}
+
+func TestPitchTrackerMedianFilter(t *testing.T) {
+	pt := newTestPitchTracker(t)
+	defer pt.Free()
+	pt.OctaveCorrection = false
+	for _, hz := range []float64{440, 440, 440, 440, 466.16, 440, 440} {
+		pt.update(hz, 0.9)
+		if p := pt.Point(); p.Voiced && math.Abs(p.Hz-440) > 0.01 {
+			t.Errorf("median filter let a transient through: %+v", p)
+		}
+	}
+}
+
+func TestPitchTrackerWithoutConfidence(t *testing.T) {
+	pt, err := NewPitchTracker(PitchSchmitt, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pt.Free()
+	// schmitt always reports a confidence of 0
+	for i := 0; i < pt.MinRun; i++ {
+		pt.update(330, 0)
+	}
+	if p := pt.Point(); !p.Voiced || math.Abs(p.Hz-330) > 0.01 {
+		t.Errorf("got %+v, want 330Hz voiced without a confidence", p)
+	}
+}
+
+func TestPitchTrackerMedianSizeZero(t *testing.T) {
+	pt := newTestPitchTracker(t)
+	defer pt.Free()
+	pt.MedianSize = 0
+	for i := 0; i < 5; i++ {
+		pt.update(440, 0.9)
+	}
+	if p := pt.Point(); math.Abs(p.Hz-440) > 0.01 {
+		t.Errorf("got %+v, want 440Hz", p)
+	}
+}
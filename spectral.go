@@ -10,18 +10,8 @@
 
 /*
 MISSING:
-AWhitening
- - all
-DCT
- - all
-FFT
- - all
-PhaseVoc
- - get win, get hop, set window
 Filterbank
  - set and get coeffs (fmat type required)
-SpecDesc
- - all
 */
 
 package aubio
@@ -33,15 +23,286 @@ package aubio
 import "C"
 
 import (
+	"fmt"
 	"log"
 )
 
 // fft
 
-// type FFT struct {
-// 	o *C.aubio_fft_t
-// 	buf *SimpleBuffer
-// }
+// FFT is a wrapper for the aubio_fft_t object. Unlike PhaseVoc it
+// transforms each buffer on its own, without windowing nor overlap.
+type FFT struct {
+	o        *C.aubio_fft_t
+	size     uint
+	grain    *ComplexBuffer
+	compspec *SimpleBuffer
+	out      *SimpleBuffer
+}
+
+// NewFFT constructs a new FFT object for buffers of size samples.
+// Depending on the FFT library aubio was built with, size may have to
+// be a power of two.
+// It is the Callers responsibility to call Free on the returned
+// FFT object or leak memory.
+//     fft, err := NewFFT(bufSize)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer fft.Free()
+func NewFFT(size uint) (*FFT, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("invalid FFT size %d", size)
+	}
+	fft, err := C.new_aubio_fft(C.uint_t(size))
+	if fft == nil {
+		return nil, fmt.Errorf("failure creating FFT object of size %d %q", size, err)
+	}
+	return &FFT{
+		o:        fft,
+		size:     size,
+		grain:    NewComplexBuffer(size),
+		compspec: NewSimpleBuffer(size),
+		out:      NewSimpleBuffer(size),
+	}, nil
+}
+
+// Size returns the number of samples the FFT transforms.
+func (fft *FFT) Size() uint {
+	return fft.size
+}
+
+// Grain returns the spectrum computed by Do.
+func (fft *FFT) Grain() *ComplexBuffer {
+	return fft.grain
+}
+
+// CompSpec returns the complex spectrum computed by DoComplex.
+// It holds the real parts of the bins 0 to size/2 followed by the
+// imaginary parts of the bins size/2-1 down to 1.
+func (fft *FFT) CompSpec() *SimpleBuffer {
+	return fft.compspec
+}
+
+// Buffer returns the signal computed by ReverseDo or ReverseDoComplex.
+func (fft *FFT) Buffer() *SimpleBuffer {
+	return fft.out
+}
+
+// Do computes the spectrum of a size input Buffer into Grain.
+func (fft *FFT) Do(in *SimpleBuffer) {
+	if fft.o == nil {
+		log.Println("Called Do on empty FFT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != fft.size {
+		log.Printf("Called Do with a buffer of %d samples, want %d", in.Size(), fft.size)
+		return
+	}
+	C.aubio_fft_do(fft.o, in.vec, fft.grain.data)
+}
+
+// ReverseDo computes the signal of a spectrum into Buffer.
+func (fft *FFT) ReverseDo(in *ComplexBuffer) {
+	if fft.o == nil {
+		log.Println("Called ReverseDo on empty FFT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != fft.grain.Size() {
+		log.Printf("Called ReverseDo with a spectrum of %d bins, want %d", in.Size(), fft.grain.Size())
+		return
+	}
+	C.aubio_fft_rdo(fft.o, in.data, fft.out.vec)
+}
+
+// DoComplex computes the complex spectrum of a size input Buffer
+// into CompSpec.
+func (fft *FFT) DoComplex(in *SimpleBuffer) {
+	if fft.o == nil {
+		log.Println("Called DoComplex on empty FFT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != fft.size {
+		log.Printf("Called DoComplex with a buffer of %d samples, want %d", in.Size(), fft.size)
+		return
+	}
+	C.aubio_fft_do_complex(fft.o, in.vec, fft.compspec.vec)
+}
+
+// ReverseDoComplex computes the signal of a complex spectrum, laid
+// out as in CompSpec, into Buffer.
+func (fft *FFT) ReverseDoComplex(compspec *SimpleBuffer) {
+	if fft.o == nil {
+		log.Println("Called ReverseDoComplex on empty FFT. Maybe you called Free previously?")
+		return
+	}
+	if compspec.Size() != fft.size {
+		log.Printf("Called ReverseDoComplex with a complex spectrum of %d samples, want %d", compspec.Size(), fft.size)
+		return
+	}
+	C.aubio_fft_rdo_complex(fft.o, compspec.vec, fft.out.vec)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (fft *FFT) Free() {
+	if fft.o != nil {
+		C.del_aubio_fft(fft.o)
+		fft.o = nil
+	}
+	if fft.grain != nil {
+		fft.grain.Free()
+		fft.grain = nil
+	}
+	if fft.compspec != nil {
+		fft.compspec.Free()
+		fft.compspec = nil
+	}
+	if fft.out != nil {
+		fft.out.Free()
+		fft.out = nil
+	}
+}
+
+// spectrumSizesMatch reports whether spectrum has the size/2+1 bins of
+// a complex spectrum of size samples, and logs the mismatch otherwise.
+// aubio does not check them and would read or write out of bounds.
+func spectrumSizesMatch(name string, compspec *SimpleBuffer, spectrum *ComplexBuffer) bool {
+	if spectrum.Size() != compspec.Size()/2+1 {
+		log.Printf("Called %s with a spectrum of %d bins for a complex spectrum of %d samples, want %d", name, spectrum.Size(), compspec.Size(), compspec.Size()/2+1)
+		return false
+	}
+	return true
+}
+
+// FFTGetSpectrum converts a complex spectrum into the norm and phase
+// of spectrum.
+func FFTGetSpectrum(compspec *SimpleBuffer, spectrum *ComplexBuffer) {
+	if !spectrumSizesMatch("FFTGetSpectrum", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_spectrum(compspec.vec, spectrum.data)
+}
+
+// FFTGetNorm computes the norm of a complex spectrum into spectrum.
+func FFTGetNorm(compspec *SimpleBuffer, spectrum *ComplexBuffer) {
+	if !spectrumSizesMatch("FFTGetNorm", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_norm(compspec.vec, spectrum.data)
+}
+
+// FFTGetPhas computes the phase of a complex spectrum into spectrum.
+func FFTGetPhas(compspec *SimpleBuffer, spectrum *ComplexBuffer) {
+	if !spectrumSizesMatch("FFTGetPhas", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_phas(compspec.vec, spectrum.data)
+}
+
+// FFTGetRealImag converts the norm and phase of spectrum into a
+// complex spectrum.
+func FFTGetRealImag(spectrum *ComplexBuffer, compspec *SimpleBuffer) {
+	if !spectrumSizesMatch("FFTGetRealImag", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_realimag(spectrum.data, compspec.vec)
+}
+
+// FFTGetReal computes the real parts of a complex spectrum from the
+// norm and phase of spectrum.
+func FFTGetReal(spectrum *ComplexBuffer, compspec *SimpleBuffer) {
+	if !spectrumSizesMatch("FFTGetReal", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_real(spectrum.data, compspec.vec)
+}
+
+// FFTGetImag computes the imaginary parts of a complex spectrum from
+// the norm and phase of spectrum.
+func FFTGetImag(spectrum *ComplexBuffer, compspec *SimpleBuffer) {
+	if !spectrumSizesMatch("FFTGetImag", compspec, spectrum) {
+		return
+	}
+	C.aubio_fft_get_imag(spectrum.data, compspec.vec)
+}
+
+// dct
+
+// DCT is a wrapper for the aubio_dct_t object, computing the
+// orthonormal type II discrete cosine transform and its inverse.
+type DCT struct {
+	o    *C.aubio_dct_t
+	size uint
+	out  *SimpleBuffer
+}
+
+// NewDCT constructs a new DCT object for buffers of size samples.
+// Depending on the FFT library aubio was built with, size may have to
+// be a power of two.
+// It is the Callers responsibility to call Free on the returned
+// DCT object or leak memory.
+//     dct, err := NewDCT(size)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer dct.Free()
+func NewDCT(size uint) (*DCT, error) {
+	if size < 2 {
+		return nil, fmt.Errorf("invalid DCT size %d", size)
+	}
+	dct, err := C.new_aubio_dct(C.uint_t(size))
+	if dct == nil {
+		return nil, fmt.Errorf("failure creating DCT object of size %d %q", size, err)
+	}
+	return &DCT{o: dct, size: size, out: NewSimpleBuffer(size)}, nil
+}
+
+// Size returns the number of samples the DCT transforms.
+func (dct *DCT) Size() uint {
+	return dct.size
+}
+
+// Buffer returns the output of the latest call to Do or ReverseDo.
+func (dct *DCT) Buffer() *SimpleBuffer {
+	return dct.out
+}
+
+// Do computes the DCT of a size input Buffer.
+func (dct *DCT) Do(in *SimpleBuffer) {
+	if dct.o == nil {
+		log.Println("Called Do on empty DCT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != dct.size {
+		log.Printf("Called Do with a buffer of %d samples, want %d", in.Size(), dct.size)
+		return
+	}
+	C.aubio_dct_do(dct.o, in.vec, dct.out.vec)
+}
+
+// ReverseDo computes the inverse DCT of a size input Buffer.
+func (dct *DCT) ReverseDo(in *SimpleBuffer) {
+	if dct.o == nil {
+		log.Println("Called ReverseDo on empty DCT. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != dct.size {
+		log.Printf("Called ReverseDo with a buffer of %d samples, want %d", in.Size(), dct.size)
+		return
+	}
+	C.aubio_dct_rdo(dct.o, in.vec, dct.out.vec)
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (dct *DCT) Free() {
+	if dct.o != nil {
+		C.del_aubio_dct(dct.o)
+		dct.o = nil
+	}
+	if dct.out != nil {
+		dct.out.Free()
+		dct.out = nil
+	}
+}
 
 // filterbank
 type FilterBank struct {
@@ -51,12 +312,26 @@ type FilterBank struct {
 }
 
 func NewFilterBank(filters uint, win_s uint) *FilterBank {
-	fbo := C.new_aubio_filterbank(C.uint_t(filters), C.uint_t(win_s))
+	fb, err := newFilterBank(filters, win_s)
+	if err != nil {
+		log.Println(err)
+		return &FilterBank{}
+	}
+	return fb
+}
+
+// newFilterBank constructs a new FilterBank, returning an error rather
+// than an empty FilterBank when aubio fails to create it.
+func newFilterBank(filters uint, win_s uint) (*FilterBank, error) {
+	fbo, err := C.new_aubio_filterbank(C.uint_t(filters), C.uint_t(win_s))
+	if fbo == nil {
+		return nil, fmt.Errorf("failure creating FilterBank object %q", err)
+	}
 	return &FilterBank{
 		o:   fbo,
 		buf: NewSimpleBuffer(filters),
 		mat: NewMatrixBufferFromFmat(C.aubio_filterbank_get_coeffs(fbo)),
-	}
+	}, nil
 }
 
 func (fb *FilterBank) Do(in *ComplexBuffer) {
@@ -99,7 +374,9 @@ func (fb *FilterBank) SetCoeffs(coeffs [][]float64) {
 		mat:    C.new_fmat(C.uint_t(fb.mat.Height), C.uint_t(fb.mat.Length)),
 	}
 	mb.SetChannels(coeffs)
+	// the coefficients are copied into the filterbank
 	C.aubio_filterbank_set_coeffs(fb.o, mb.mat)
+	mb.Free()
 }
 
 // The coeffs will be normalized by the triangles area which results in an uneven melbank.
@@ -116,6 +393,20 @@ func (fb *FilterBank) SetMelCoeffs(sample uint, fmin uint, fmax uint) {
 	C.aubio_filterbank_set_mel_coeffs(fb.o, C.smpl_t(sample), C.smpl_t(fmin), C.smpl_t(fmax))
 }
 
+// Free frees the memory allocated by the aubio library for this object.
+func (fb *FilterBank) Free() {
+	if fb.o != nil {
+		C.del_aubio_filterbank(fb.o)
+		fb.o = nil
+		// the coefficients are owned by the filterbank
+		fb.mat = nil
+	}
+	if fb.buf != nil {
+		fb.buf.Free()
+		fb.buf = nil
+	}
+}
+
 func (fb *FilterBank) Buffer() *SimpleBuffer {
 	return fb.buf
 }
@@ -148,11 +439,22 @@ func (fb *FilterBank) NormalizeCoeffs() {
 
 // mfcc
 
+// MFCC is a wrapper for the aubio_mfcc_t object. It computes the Mel
+// frequency cepstral coefficients of the spectra of a PhaseVoc.
 type MFCC struct {
 	o      *C.aubio_mfcc_t
 	coeffs *SimpleBuffer
 }
 
+// NewMFCC constructs a new MFCC object computing n_coeffs coefficients
+// from n_filters Mel bands, for spectra of a bufSize PhaseVoc.
+// It is the Callers responsibility to call Free on the returned
+// MFCC object or leak memory.
+//     mfcc, err := NewMFCC(bufSize, samplerate, 13, 40)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer mfcc.Free()
 func NewMFCC(bufSize, samplerate, n_coeffs, n_filters uint) (*MFCC, error) {
 	mfcc, err := C.new_aubio_mfcc(
 		C.uint_t(bufSize),
@@ -160,12 +462,12 @@ func NewMFCC(bufSize, samplerate, n_coeffs, n_filters uint) (*MFCC, error) {
 		C.uint_t(n_coeffs),
 		C.uint_t(samplerate),
 	)
-	if err != nil {
-		return nil, err
+	if mfcc == nil {
+		return nil, fmt.Errorf("failure creating MFCC object %q", err)
 	}
 	return &MFCC{
 		o:      mfcc,
-		coeffs: NewSimpleBuffer(bufSize)}, nil
+		coeffs: NewSimpleBuffer(n_coeffs)}, nil
 }
 
 func (mfcc *MFCC) Free() {
@@ -179,12 +481,14 @@ func (mfcc *MFCC) Free() {
 	}
 }
 
+// Coeffs returns the n_coeffs coefficients computed by the latest call
+// to Do.
 func (mfcc *MFCC) Coeffs() *SimpleBuffer {
 	return mfcc.coeffs
 }
 
 func (mfcc *MFCC) Do(in *ComplexBuffer) {
-	if mfcc != nil || mfcc.o != nil {
+	if mfcc.o != nil {
 		C.aubio_mfcc_do(mfcc.o, in.data, mfcc.coeffs.vec)
 	} else {
 		log.Println("Called Do on empty MFCC. Maybe you called Free previously?")
@@ -192,44 +496,90 @@ func (mfcc *MFCC) Do(in *ComplexBuffer) {
 }
 
 func (mfcc *MFCC) SetScale(scale float64) {
+	if mfcc.o == nil {
+		return
+	}
 	C.aubio_mfcc_set_scale(mfcc.o, C.smpl_t(scale))
 }
 
 func (mfcc *MFCC) GetScale() float64 {
+	if mfcc.o == nil {
+		return 0
+	}
 	return float64(C.aubio_mfcc_get_scale(mfcc.o))
 }
 
 func (mfcc *MFCC) SetPower(power float64) {
+	if mfcc.o == nil {
+		return
+	}
 	C.aubio_mfcc_set_power(mfcc.o, C.smpl_t(power))
 }
 
 func (mfcc *MFCC) GetPower() float64 {
+	if mfcc.o == nil {
+		return 0
+	}
 	return float64(C.aubio_mfcc_get_power(mfcc.o))
 }
 
 func (mfcc *MFCC) SetMelCoeffsSlaney() {
+	if mfcc.o == nil {
+		return
+	}
 	C.aubio_mfcc_set_mel_coeffs_slaney(mfcc.o)
 }
 
 func (mfcc *MFCC) SetMelCoeffsHTK(fmin uint, fmax uint) {
+	if mfcc.o == nil {
+		return
+	}
 	C.aubio_mfcc_set_mel_coeffs_htk(mfcc.o, C.smpl_t(fmin), C.smpl_t(fmax))
 }
 
 func (mfcc *MFCC) SetMelCoeffs(fmin uint, fmax uint) {
+	if mfcc.o == nil {
+		return
+	}
 	C.aubio_mfcc_set_mel_coeffs(mfcc.o, C.smpl_t(fmin), C.smpl_t(fmax))
 }
 
 // phasvoc
 
+// PhaseVoc is a wrapper for the aubio_pvoc_t phase vocoder object.
+// It computes the spectrum of overlapping windows of the signal, and
+// resynthesizes the signal from them by overlap-add.
 type PhaseVoc struct {
 	o     *C.aubio_pvoc_t
 	grain *ComplexBuffer
 }
 
-func NewPhaseVoc(bufSize, fftLen uint) (*PhaseVoc, error) {
-	pvoc, err := C.new_aubio_pvoc(C.uint_t(bufSize), C.uint_t(fftLen))
-	if err != nil {
-		return nil, err
+// SpectralFrame holds the norm and phase of a PhaseVoc spectrum.
+type SpectralFrame struct {
+	Norm  []float64
+	Phase []float64
+}
+
+// SpectralAnalysis holds the spectral frames of a signal along with
+// its length in samples, to which the resynthesis is trimmed.
+type SpectralAnalysis struct {
+	Frames []SpectralFrame
+	Length int
+}
+
+// NewPhaseVoc constructs a new PhaseVoc object analyzing windows of
+// bufSize samples every hopSize samples.
+// It is the Callers responsibility to call Free on the returned
+// PhaseVoc object or leak memory.
+//     pv, err := NewPhaseVoc(bufSize, hopSize)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer pv.Free()
+func NewPhaseVoc(bufSize, hopSize uint) (*PhaseVoc, error) {
+	pvoc, err := C.new_aubio_pvoc(C.uint_t(bufSize), C.uint_t(hopSize))
+	if pvoc == nil {
+		return nil, fmt.Errorf("failure creating PhaseVoc object %q", err)
 	}
 	return &PhaseVoc{
 		o:     pvoc,
@@ -251,8 +601,38 @@ func (pv *PhaseVoc) Grain() *ComplexBuffer {
 	return pv.grain
 }
 
+// SetWindow selects the analysis and synthesis window.
+func (pv *PhaseVoc) SetWindow(window WindowType) error {
+	if err := window.validate(); err != nil {
+		return err
+	}
+	if pv.o == nil {
+		return fmt.Errorf("called SetWindow on empty PhaseVoc")
+	}
+	if C.aubio_pvoc_set_window(pv.o, toCharTPtr(string(window))) != 0 {
+		return fmt.Errorf("failure setting PhaseVoc window %q", window)
+	}
+	return nil
+}
+
+// GetWin returns the window size in samples.
+func (pv *PhaseVoc) GetWin() uint {
+	if pv.o == nil {
+		return 0
+	}
+	return uint(C.aubio_pvoc_get_win(pv.o))
+}
+
+// GetHop returns the hop size in samples.
+func (pv *PhaseVoc) GetHop() uint {
+	if pv.o == nil {
+		return 0
+	}
+	return uint(C.aubio_pvoc_get_hop(pv.o))
+}
+
 func (pv *PhaseVoc) Do(in *SimpleBuffer) {
-	if pv != nil || pv.o != nil {
+	if pv.o != nil {
 		C.aubio_pvoc_do(pv.o, in.vec, pv.grain.data)
 	} else {
 		log.Println("Called Do on empty PhaseVoc. Maybe you called Free previously?")
@@ -267,6 +647,250 @@ func (pv *PhaseVoc) ReverseDo(out *SimpleBuffer) {
 	}
 }
 
+// AnalyzeSamples computes the spectrum of every hop of samples. The
+// signal is padded with zeros so that ResynthesizeSamples can restore
+// all of it despite the latency of the overlap-add.
+func (pv *PhaseVoc) AnalyzeSamples(samples []float64) *SpectralAnalysis {
+	if pv.o == nil {
+		log.Println("Called AnalyzeSamples on empty PhaseVoc. Maybe you called Free previously?")
+		return nil
+	}
+	hop := int(pv.GetHop())
+	in := NewSimpleBuffer(uint(hop))
+	defer in.Free()
+	frame := make([]float64, hop)
+	latency := int(pv.GetWin()) - hop
+	var frames []SpectralFrame
+	for start := 0; start < len(samples)+latency; start += hop {
+		for i := range frame {
+			frame[i] = 0
+			if start+i < len(samples) {
+				frame[i] = samples[start+i]
+			}
+		}
+		in.SetData(frame)
+		pv.Do(in)
+		frames = append(frames, SpectralFrame{Norm: pv.grain.Norm(), Phase: pv.grain.Phase()})
+	}
+	return &SpectralAnalysis{Frames: frames, Length: len(samples)}
+}
+
+// ResynthesizeSamples turns the frames of a back into a signal by
+// overlap-add, dropping the latency of the PhaseVoc and the padding of
+// the last hop so that the output lines up with the signal given to
+// AnalyzeSamples.
+func (pv *PhaseVoc) ResynthesizeSamples(a *SpectralAnalysis) []float64 {
+	if pv.o == nil {
+		log.Println("Called ResynthesizeSamples on empty PhaseVoc. Maybe you called Free previously?")
+		return nil
+	}
+	if a == nil {
+		return nil
+	}
+	hop := int(pv.GetHop())
+	out := NewSimpleBuffer(uint(hop))
+	defer out.Free()
+	latency := int(pv.GetWin()) - hop
+	samples := make([]float64, 0, len(a.Frames)*hop)
+	for _, f := range a.Frames {
+		pv.grain.SetNorm(f.Norm)
+		pv.grain.SetPhase(f.Phase)
+		pv.ReverseDo(out)
+		samples = append(samples, out.Slice()...)
+	}
+	if len(samples) < latency {
+		return nil
+	}
+	samples = samples[latency:]
+	if len(samples) > a.Length {
+		samples = samples[:a.Length]
+	}
+	return samples
+}
+
+// Analyze reads the whole of src, whose block size must be the hop
+// size of the PhaseVoc, and returns its spectral frames.
+func (pv *PhaseVoc) Analyze(src *Source) (*SpectralAnalysis, error) {
+	if src.BlockSize() != pv.GetHop() {
+		return nil, fmt.Errorf("source block size %d does not match the PhaseVoc hop size %d",
+			src.BlockSize(), pv.GetHop())
+	}
+	buf := NewSimpleBuffer(src.BlockSize())
+	defer buf.Free()
+	var samples []float64
+	for {
+		n := src.Do(buf)
+		samples = append(samples, buf.Slice()[:n]...)
+		if n < src.BlockSize() {
+			break
+		}
+	}
+	return pv.AnalyzeSamples(samples), nil
+}
+
+// Synthesize resynthesizes the frames of a from Analyze and writes as
+// many samples as were analysed to sink. Use a PhaseVoc whose
+// synthesis has not been used yet.
+func (pv *PhaseVoc) Synthesize(a *SpectralAnalysis, sink *Sink) error {
+	hop := pv.GetHop()
+	if hop == 0 {
+		return fmt.Errorf("called Synthesize on empty PhaseVoc")
+	}
+	samples := pv.ResynthesizeSamples(a)
+	buf := NewSimpleBuffer(hop)
+	defer buf.Free()
+	frame := make([]float64, hop)
+	for start := 0; start < len(samples); start += int(hop) {
+		n := copy(frame, samples[start:])
+		for i := n; i < len(frame); i++ {
+			frame[i] = 0
+		}
+		buf.SetData(frame)
+		sink.Do(buf, uint(n))
+	}
+	return nil
+}
+
+// specdesc
+
+// SpecDesc is a wrapper for the aubio_specdesc_t object. It computes
+// a single onset detection or spectral shape value for each
+// ComplexBuffer frame, typically the output of PhaseVoc.
+type SpecDesc struct {
+	o      *C.aubio_specdesc_t
+	method SpecDescMethod
+	buf    *SimpleBuffer
+}
+
+// NewSpecDesc constructs a new SpecDesc object for the given method.
+// bufSize must match the window size of the PhaseVoc feeding it.
+// It is the Callers responsibility to call Free on the returned
+// SpecDesc object or leak memory.
+//     sd, err := NewSpecDesc(SpecDescCentroid, bufSize)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer sd.Free()
+func NewSpecDesc(method SpecDescMethod, bufSize uint) (*SpecDesc, error) {
+	if err := method.validate(); err != nil {
+		return nil, err
+	}
+	sd, err := C.new_aubio_specdesc(toCharTPtr(string(method)), C.uint_t(bufSize))
+	if sd == nil {
+		return nil, fmt.Errorf("failure creating SpecDesc object %q", err)
+	}
+	return &SpecDesc{
+		o:      sd,
+		method: method,
+		buf:    NewSimpleBuffer(1)}, nil
+}
+
+func (sd *SpecDesc) Free() {
+	if sd.o != nil {
+		C.del_aubio_specdesc(sd.o)
+		sd.o = nil
+	}
+	if sd.buf != nil {
+		sd.buf.Free()
+		sd.buf = nil
+	}
+}
+
+// Method returns the SpecDescMethod this SpecDesc computes.
+func (sd *SpecDesc) Method() SpecDescMethod {
+	return sd.method
+}
+
+// Buffer returns the single sample output buffer for this SpecDesc.
+func (sd *SpecDesc) Buffer() *SimpleBuffer {
+	return sd.buf
+}
+
+// Value returns the descriptor computed by the most recent call to Do.
+func (sd *SpecDesc) Value() float64 {
+	if sd.o == nil {
+		return 0
+	}
+	return sd.buf.Get(0)
+}
+
+func (sd *SpecDesc) Do(in *ComplexBuffer) {
+	if sd.o != nil {
+		C.aubio_specdesc_do(sd.o, in.data, sd.buf.vec)
+	} else {
+		log.Println("Called Do on empty SpecDesc. Maybe you called Free previously?")
+	}
+}
+
+// ShapeFrame holds the spectral shape descriptors of a single frame.
+// Centroid and Rolloff are expressed in FFT bins.
+type ShapeFrame struct {
+	Centroid float64
+	Spread   float64
+	Skewness float64
+	Kurtosis float64
+	Slope    float64
+	Decrease float64
+	Rolloff  float64
+}
+
+// SpectralShape computes every spectral shape descriptor of a frame
+// in one pass.
+type SpectralShape struct {
+	descs []*SpecDesc
+	frame ShapeFrame
+}
+
+// NewSpectralShape constructs a new SpectralShape extractor.
+// It is the Callers responsibility to call Free on the returned
+// SpectralShape object or leak memory.
+func NewSpectralShape(bufSize uint) (*SpectralShape, error) {
+	methods := []SpecDescMethod{SpecDescCentroid, SpecDescSpread,
+		SpecDescSkewness, SpecDescKurtosis, SpecDescSlope, SpecDescDecrease,
+		SpecDescRolloff}
+	s := &SpectralShape{}
+	for _, m := range methods {
+		sd, err := NewSpecDesc(m, bufSize)
+		if err != nil {
+			s.Free()
+			return nil, err
+		}
+		s.descs = append(s.descs, sd)
+	}
+	return s, nil
+}
+
+func (s *SpectralShape) Free() {
+	for _, sd := range s.descs {
+		sd.Free()
+	}
+	s.descs = nil
+}
+
+// Frame returns the descriptors computed by the most recent call to Do.
+func (s *SpectralShape) Frame() ShapeFrame {
+	return s.frame
+}
+
+func (s *SpectralShape) Do(in *ComplexBuffer) {
+	if s.descs == nil {
+		log.Println("Called Do on empty SpectralShape. Maybe you called Free previously?")
+		return
+	}
+	for _, sd := range s.descs {
+		sd.Do(in)
+	}
+	s.frame = ShapeFrame{
+		Centroid: s.descs[0].Value(),
+		Spread:   s.descs[1].Value(),
+		Skewness: s.descs[2].Value(),
+		Kurtosis: s.descs[3].Value(),
+		Slope:    s.descs[4].Value(),
+		Decrease: s.descs[5].Value(),
+		Rolloff:  s.descs[6].Value(),
+	}
+}
+
 // statistics
 
 // tss
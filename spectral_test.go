@@ -0,0 +1,411 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+var _ ReverseComplexAnalyzer = (*FFT)(nil)
+
+func TestFFTRoundTrip(t *testing.T) {
+	const size = 512
+	fft, err := NewFFT(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fft.Free()
+	signal := tone(testSamplerate*32./size, testSamplerate, size)
+	in := NewSimpleBufferData(size, signal)
+	defer in.Free()
+
+	fft.Do(in)
+	norm := fft.Grain().Norm()
+	if len(norm) != size/2+1 {
+		t.Fatalf("spectrum has %d bins, want %d", len(norm), size/2+1)
+	}
+	peak := 0
+	for k := range norm {
+		if norm[k] > norm[peak] {
+			peak = k
+		}
+	}
+	if peak != 32 {
+		t.Errorf("peak in bin %d, want 32", peak)
+	}
+	fft.ReverseDo(fft.Grain())
+	for i, v := range fft.Buffer().Slice() {
+		if math.Abs(v-signal[i]) > 1e-4 {
+			t.Fatalf("ReverseDo sample %d = %v, want %v", i, v, signal[i])
+		}
+	}
+
+	fft.DoComplex(in)
+	spectrum := NewComplexBuffer(size)
+	defer spectrum.Free()
+	FFTGetSpectrum(fft.CompSpec(), spectrum)
+	for k, v := range spectrum.Norm() {
+		if math.Abs(v-norm[k]) > 1e-3 {
+			t.Fatalf("FFTGetSpectrum bin %d = %v, want %v", k, v, norm[k])
+		}
+	}
+	compspec := NewSimpleBuffer(size)
+	defer compspec.Free()
+	FFTGetRealImag(spectrum, compspec)
+	fft.ReverseDoComplex(compspec)
+	for i, v := range fft.Buffer().Slice() {
+		if math.Abs(v-signal[i]) > 1e-4 {
+			t.Fatalf("ReverseDoComplex sample %d = %v, want %v", i, v, signal[i])
+		}
+	}
+}
+
+func TestFFTInvalidSize(t *testing.T) {
+	for _, size := range []uint{0, 1} {
+		if fft, err := NewFFT(size); err == nil {
+			fft.Free()
+			t.Errorf("NewFFT(%d) should fail", size)
+		}
+	}
+}
+
+func TestFFTSizeMismatch(t *testing.T) {
+	const size = 512
+	fft, err := NewFFT(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fft.Free()
+	short := NewSimpleBufferData(size/2, tone(1000, testSamplerate, size/2))
+	defer short.Free()
+	fft.DoComplex(short)
+	fft.Do(short)
+	for i, x := range fft.CompSpec().Slice() {
+		if x != 0 {
+			t.Fatalf("compspec[%d] = %v after a short buffer, want it untouched", i, x)
+		}
+	}
+	for i, x := range fft.Grain().Norm() {
+		if x != 0 {
+			t.Fatalf("norm[%d] = %v after a short buffer, want it untouched", i, x)
+		}
+	}
+	spectrum := NewComplexBuffer(size / 2)
+	defer spectrum.Free()
+	fft.ReverseDo(spectrum)
+	compspec := NewSimpleBuffer(size)
+	defer compspec.Free()
+	compspec.SetData([]float64{1})
+	FFTGetNorm(compspec, spectrum)
+	for i, x := range spectrum.Norm() {
+		if x != 0 {
+			t.Fatalf("norm[%d] = %v for a mismatched spectrum, want it untouched", i, x)
+		}
+	}
+	ones := make([]float64, size/2)
+	for i := range ones {
+		ones[i] = 1
+	}
+	spectrum.SetNorm(ones)
+	spectrum.SetPhase(ones)
+	imag := NewSimpleBuffer(size)
+	defer imag.Free()
+	FFTGetImag(spectrum, imag)
+	for i, x := range imag.Slice() {
+		if x != 0 {
+			t.Fatalf("imag[%d] = %v for a mismatched spectrum, want it untouched", i, x)
+		}
+	}
+}
+
+func TestDCTRoundTrip(t *testing.T) {
+	const size = 16
+	dct, err := NewDCT(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dct.Free()
+	signal := make([]float64, size)
+	for i := range signal {
+		signal[i] = math.Sin(float64(i)) + 0.5
+	}
+	in := NewSimpleBufferData(size, signal)
+	defer in.Free()
+	dct.Do(in)
+	coeffs := NewSimpleBufferData(size, dct.Buffer().Slice())
+	defer coeffs.Free()
+	// orthonormal: energy is preserved
+	var e1, e2 float64
+	for i := range signal {
+		e1 += signal[i] * signal[i]
+		e2 += coeffs.Get(uint(i)) * coeffs.Get(uint(i))
+	}
+	if math.Abs(e1-e2) > 1e-4 {
+		t.Errorf("energy %v after DCT, want %v", e2, e1)
+	}
+	dct.ReverseDo(coeffs)
+	for i, v := range dct.Buffer().Slice() {
+		if math.Abs(v-signal[i]) > 1e-4 {
+			t.Fatalf("ReverseDo sample %d = %v, want %v", i, v, signal[i])
+		}
+	}
+}
+
+func TestDCTSizeMismatch(t *testing.T) {
+	const size = 16
+	dct, err := NewDCT(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dct.Free()
+	short := NewSimpleBufferData(size/2, tone(1000, testSamplerate, size/2))
+	defer short.Free()
+	dct.Do(short)
+	dct.ReverseDo(short)
+	for i, x := range dct.Buffer().Slice() {
+		if x != 0 {
+			t.Fatalf("out[%d] = %v after a short buffer, want it untouched", i, x)
+		}
+	}
+}
+
+func TestPhaseVocReconstruction(t *testing.T) {
+	pv, err := NewPhaseVoc(4*testHopSize, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pv.Free()
+	if pv.GetWin() != 4*testHopSize || pv.GetHop() != testHopSize {
+		t.Errorf("win %d hop %d, want %d and %d", pv.GetWin(), pv.GetHop(), 4*testHopSize, testHopSize)
+	}
+	if err := pv.SetWindow("triangle"); err == nil {
+		t.Error("expected an error for an unknown window")
+	}
+	signal := tone(440, testSamplerate, testSamplerate/4)
+	for i, v := range tone(1234, testSamplerate, len(signal)) {
+		signal[i] = 0.5*signal[i] + 0.3*v
+	}
+	// not a whole number of hops, so the last one is padded
+	analysis := pv.AnalyzeSamples(signal)
+	if analysis.Length != len(signal) {
+		t.Errorf("analysis length %d, want %d", analysis.Length, len(signal))
+	}
+	out := pv.ResynthesizeSamples(analysis)
+	if len(out) != len(signal) {
+		t.Fatalf("got %d samples back, want %d", len(out), len(signal))
+	}
+	var maxErr float64
+	for i := range signal {
+		maxErr = math.Max(maxErr, math.Abs(out[i]-signal[i]))
+	}
+	if maxErr > 1e-4 {
+		t.Errorf("resynthesis is off by up to %v", maxErr)
+	}
+}
+
+func TestPhaseVocSourceToSink(t *testing.T) {
+	dir := t.TempDir()
+	in, out := dir+"/in.wav", dir+"/out.wav"
+	signal := tone(440, testSamplerate, testSamplerate/4)
+	sink, err := OpenSink(in, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+		buf.SetData(signal[i : i+testHopSize])
+		sink.Do(buf, testHopSize)
+	}
+	sink.Close()
+	signal = signal[:len(signal)/testHopSize*testHopSize]
+
+	pv, err := NewPhaseVoc(4*testHopSize, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pv.Free()
+	src, err := OpenSource(in, testSamplerate, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, err := pv.Analyze(src)
+	src.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sink, err = OpenSink(out, testSamplerate); err != nil {
+		t.Fatal(err)
+	}
+	if err := pv.Synthesize(analysis, sink); err != nil {
+		t.Fatal(err)
+	}
+	sink.Close()
+
+	if src, err = OpenSource(out, testSamplerate, testHopSize); err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	var got []float64
+	for {
+		n := src.Do(buf)
+		got = append(got, buf.Slice()[:n]...)
+		if n < testHopSize {
+			break
+		}
+	}
+	if len(got) != len(signal) {
+		t.Fatalf("read back %d samples, want %d", len(got), len(signal))
+	}
+	for i := range signal {
+		// allow for the 16 bit quantization of the files
+		if math.Abs(got[i]-signal[i]) > 1e-3 {
+			t.Fatalf("sample %d = %v, want %v", i, got[i], signal[i])
+		}
+	}
+}
+
+func TestSpectralWhitening(t *testing.T) {
+	pv, err := NewPhaseVoc(testBufSize, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pv.Free()
+	sw, err := NewSpectralWhitening(testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sw.Free()
+	if err := sw.SetRelaxTime(10); err != nil || sw.GetRelaxTime() != 10 {
+		t.Errorf("relax time %v (%v), want 10", sw.GetRelaxTime(), err)
+	}
+	if err := sw.SetFloor(1e-3); err != nil || math.Abs(sw.GetFloor()-1e-3) > 1e-9 {
+		t.Errorf("floor %v (%v), want 1e-3", sw.GetFloor(), err)
+	}
+	if sw.SetRelaxTime(0) == nil || sw.SetFloor(-1) == nil {
+		t.Error("expected errors for a zero relax time and a negative floor")
+	}
+	// a loud and a quiet tone both peak at 1 once whitened
+	for _, gain := range []float64{1, 0.01} {
+		sw.Reset()
+		signal := tone(440, testSamplerate, testBufSize)
+		for i := range signal {
+			signal[i] *= gain
+		}
+		buf := NewSimpleBuffer(testHopSize)
+		for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+			buf.SetData(signal[i : i+testHopSize])
+			pv.Do(buf)
+		}
+		buf.Free()
+		sw.Do(pv.Grain())
+		var peak float64
+		for _, v := range pv.Grain().Norm() {
+			peak = math.Max(peak, v)
+		}
+		if math.Abs(peak-1) > 1e-3 {
+			t.Errorf("gain %v: whitened peak %v, want 1", gain, peak)
+		}
+	}
+}
+
+// triangleSpectrum returns a spectrum of bufSize whose norm is a
+// triangle centred on bin center and width bins wide either side.
+func triangleSpectrum(bufSize uint, center, width int) *ComplexBuffer {
+	grain := NewComplexBuffer(bufSize)
+	norm := make([]float64, bufSize/2+1)
+	for k := center - width; k <= center+width; k++ {
+		norm[k] = float64(width + 1 - absInt(k-center))
+	}
+	grain.SetNorm(norm)
+	return grain
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestSpecDesc(t *testing.T) {
+	grain := triangleSpectrum(testBufSize, 100, 10)
+	defer grain.Free()
+	sd, err := NewSpecDesc(SpecDescCentroid, testBufSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sd.Free()
+	if sd.Method() != SpecDescCentroid {
+		t.Errorf("method %q, want %q", sd.Method(), SpecDescCentroid)
+	}
+	sd.Do(grain)
+	if got := sd.Value(); math.Abs(got-100) > 1e-3 {
+		t.Errorf("centroid %v, want bin 100", got)
+	}
+	if _, err := NewSpecDesc("centroids", testBufSize); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+	sd.Free()
+	if got := sd.Value(); got != 0 {
+		t.Errorf("value %v after Free, want 0", got)
+	}
+}
+
+func TestSpectralShape(t *testing.T) {
+	shape, err := NewSpectralShape(testBufSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shape.Free()
+	narrow := triangleSpectrum(testBufSize, 200, 2)
+	defer narrow.Free()
+	wide := triangleSpectrum(testBufSize, 200, 50)
+	defer wide.Free()
+
+	shape.Do(narrow)
+	n := shape.Frame()
+	shape.Do(wide)
+	w := shape.Frame()
+	for _, f := range []ShapeFrame{n, w} {
+		if math.Abs(f.Centroid-200) > 1e-3 {
+			t.Errorf("centroid %v, want bin 200", f.Centroid)
+		}
+		// a symmetric spectrum is not skewed
+		if math.Abs(f.Skewness) > 1e-3 {
+			t.Errorf("skewness %v, want 0", f.Skewness)
+		}
+		if f.Rolloff < 200 || f.Rolloff > 250 {
+			t.Errorf("rolloff %v, want between bins 200 and 250", f.Rolloff)
+		}
+	}
+	if w.Spread <= n.Spread {
+		t.Errorf("spread of the wide spectrum %v, want more than %v", w.Spread, n.Spread)
+	}
+}
+
+func TestPhaseVocFree(t *testing.T) {
+	pv, err := NewPhaseVoc(4*testHopSize, testHopSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pv.Free()
+	// must not crash once freed
+	if analysis := pv.AnalyzeSamples(make([]float64, testHopSize)); analysis != nil {
+		t.Errorf("got %d frames once freed", len(analysis.Frames))
+	}
+	if samples := pv.ResynthesizeSamples(&SpectralAnalysis{Frames: []SpectralFrame{{}}, Length: testHopSize}); samples != nil {
+		t.Errorf("got %d samples once freed", len(samples))
+	}
+}
+
+func TestFilterBankInvalid(t *testing.T) {
+	if _, err := newFilterBank(0, testBufSize); err == nil {
+		t.Error("expected an error for a filterbank without filters")
+	}
+	// must not crash on the empty FilterBank returned instead
+	fb := NewFilterBank(0, testBufSize)
+	if fb.o != nil {
+		t.Error("expected an empty FilterBank")
+	}
+	fb.Free()
+}
@@ -0,0 +1,234 @@
+package aubio
+
+import (
+	"math"
+	"sort"
+)
+
+// TapFusion selects how TapTempo combines taps with detected beats.
+type TapFusion int
+
+const (
+	// TapsOnly follows the taps and ignores the detector.
+	TapsOnly TapFusion = iota
+	// DetectionOnly follows the detector and ignores the taps.
+	DetectionOnly
+	// TapsAsPrior follows the phase of the detector, with its tempo
+	// halved or doubled to be closest to the tapped tempo.
+	TapsAsPrior
+)
+
+// TapTempo estimates the tempo and phase from taps, and fuses them
+// with the beats detected by Tempo through a BeatClock. It implements
+// BeatPredictor like BeatClock, so the source of the beats can be
+// switched without changing the code consuming them.
+type TapTempo struct {
+	clock    *BeatClock
+	taps     []float64
+	period   float64
+	anchor   float64
+	lastTick float64
+	ticked   bool
+
+	// Mode selects how taps and detected beats are combined.
+	// Defaults to TapsAsPrior.
+	Mode TapFusion
+	// MaxTaps is the number of most recent taps the estimate uses.
+	// Defaults to 8.
+	MaxTaps int
+	// Timeout is the gap in seconds between two taps after which the
+	// previous taps are forgotten. Defaults to 2s.
+	Timeout float64
+	// Tolerance is the fraction of the period by which a tap may be
+	// off the tapped grid before it is rejected. Defaults to 0.2.
+	Tolerance float64
+	// Lookahead is how early in seconds Ticked fires before each
+	// predicted fused beat.
+	Lookahead float64
+}
+
+// NewTapTempo constructs a new TapTempo whose detector BeatClock
+// advances by hopSize samples on each call to Do.
+func NewTapTempo(hopSize, samplerate uint) *TapTempo {
+	return &TapTempo{
+		clock:     NewBeatClock(hopSize, samplerate),
+		lastTick:  math.Inf(-1),
+		Mode:      TapsAsPrior,
+		MaxTaps:   8,
+		Timeout:   2,
+		Tolerance: 0.2,
+	}
+}
+
+// Clock returns the BeatClock following the detector.
+func (tt *TapTempo) Clock() *BeatClock {
+	return tt.clock
+}
+
+// Do advances the clock by one hop and synchronizes the detector with
+// t, on which Do must have just been called.
+func (tt *TapTempo) Do(t *Tempo) {
+	tt.clock.Do(t)
+	tt.tick(tt.clock.hop)
+}
+
+// Advance moves the clock forward by dt seconds.
+func (tt *TapTempo) Advance(dt float64) {
+	tt.clock.Advance(dt)
+	tt.tick(dt)
+}
+
+// tick updates Ticked after the clock moved forward by dt seconds, as
+// BeatClock.Advance does for the detected beats.
+func (tt *TapTempo) tick(dt float64) {
+	tt.ticked = false
+	period, anchor, ok := tt.grid()
+	if !ok {
+		return
+	}
+	from := math.Max(tt.lastTick+period/2, tt.Now()+tt.Lookahead-dt)
+	next := anchor + math.Ceil((from-anchor)/period)*period
+	if next <= tt.Now()+tt.Lookahead {
+		tt.ticked = true
+		tt.lastTick = next
+	}
+}
+
+// Tap adds a tap at t seconds, usually Now.
+func (tt *TapTempo) Tap(t float64) {
+	if n := len(tt.taps); n > 0 && (t-tt.taps[n-1] > tt.Timeout || t <= tt.taps[n-1]) {
+		tt.taps = tt.taps[:0]
+	}
+	tt.taps = append(tt.taps, t)
+	if len(tt.taps) > tt.MaxTaps {
+		tt.taps = tt.taps[len(tt.taps)-tt.MaxTaps:]
+	}
+	tt.estimate()
+}
+
+// TapBPM returns the tempo of the taps alone, or 0 when there are not
+// enough of them.
+func (tt *TapTempo) TapBPM() float64 {
+	if tt.period == 0 {
+		return 0
+	}
+	return 60 / tt.period
+}
+
+// Reset forgets the taps.
+func (tt *TapTempo) Reset() {
+	tt.taps = nil
+	tt.period = 0
+}
+
+// estimate fits a grid of beats through the taps. Taps that fall too
+// far from the grid are rejected, and missed taps are allowed for.
+func (tt *TapTempo) estimate() {
+	if len(tt.taps) < 3 {
+		return
+	}
+	intervals := make([]float64, len(tt.taps)-1)
+	for i := range intervals {
+		intervals[i] = tt.taps[i+1] - tt.taps[i]
+	}
+	sort.Float64s(intervals)
+	period := intervals[len(intervals)/2]
+	// fit t = anchor + k*period over the taps on the grid
+	var sk, st, skk, skt, n float64
+	first := tt.taps[0]
+	for _, t := range tt.taps {
+		k := math.Round((t - first) / period)
+		if math.Abs(t-first-k*period) > tt.Tolerance*period {
+			continue
+		}
+		sk += k
+		st += t
+		skk += k * k
+		skt += k * t
+		n++
+	}
+	if n < 3 || n*skk == sk*sk {
+		return
+	}
+	tt.period = (n*skt - sk*st) / (n*skk - sk*sk)
+	tt.anchor = (st - tt.period*sk) / n
+}
+
+// grid returns the period and anchor of the beats followed by Mode.
+func (tt *TapTempo) grid() (period, anchor float64, ok bool) {
+	tapped := tt.period > 0
+	detected := tt.clock.Running()
+	switch {
+	case tt.Mode == TapsOnly || tt.Mode == TapsAsPrior && !detected:
+		return tt.period, tt.anchor, tapped
+	case tt.Mode == DetectionOnly || !tapped:
+		return tt.clock.period, tt.clock.anchor, detected
+	}
+	period = tt.clock.period
+	for _, f := range []float64{0.5, 2} {
+		if math.Abs(math.Log2(tt.clock.period*f/tt.period)) < math.Abs(math.Log2(period/tt.period)) {
+			period = tt.clock.period * f
+		}
+	}
+	return period, tt.clock.anchor, true
+}
+
+// Now returns the time of the clock in seconds.
+func (tt *TapTempo) Now() float64 {
+	return tt.clock.Now()
+}
+
+// Running returns if a fused tempo is known, from the taps or from a
+// running detector as selected by Mode.
+func (tt *TapTempo) Running() bool {
+	_, _, ok := tt.grid()
+	return ok
+}
+
+// Ticked returns if the virtual metronome ticked during the latest
+// call to Do or Advance, Lookahead seconds before a predicted fused
+// beat.
+func (tt *TapTempo) Ticked() bool {
+	return tt.ticked
+}
+
+// BPM returns the fused tempo, or 0 when none is known.
+func (tt *TapTempo) BPM() float64 {
+	period, _, ok := tt.grid()
+	if !ok {
+		return 0
+	}
+	return 60 / period
+}
+
+// Phase returns the position of Now between two fused beats, from 0
+// to 1. It is 0 when no tempo is known.
+func (tt *TapTempo) Phase() float64 {
+	period, anchor, ok := tt.grid()
+	if !ok {
+		return 0
+	}
+	phase := math.Mod((tt.Now()-anchor)/period, 1)
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+// NextBeats returns the predicted times in seconds of the next n fused
+// beats strictly after Now. It returns nil when no tempo is known.
+func (tt *TapTempo) NextBeats(n int) []float64 {
+	period, anchor, ok := tt.grid()
+	if !ok || n <= 0 {
+		return nil
+	}
+	next := anchor + math.Ceil((tt.Now()-anchor)/period)*period
+	if next <= tt.Now() {
+		next += period
+	}
+	beats := make([]float64, n)
+	for i := range beats {
+		beats[i] = next + float64(i)*period
+	}
+	return beats
+}
@@ -0,0 +1,109 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	_ BeatPredictor = (*BeatClock)(nil)
+	_ BeatPredictor = (*TapTempo)(nil)
+)
+
+func TestTapTempo(t *testing.T) {
+	tt := NewTapTempo(testHopSize, testSamplerate)
+	tt.Mode = TapsOnly
+	// 100 BPM with some jitter, a missed tap and a stray double tap
+	for _, tap := range []float64{1.0, 1.61, 2.19, 2.8, 4.0, 4.02, 4.6, 5.21} {
+		tt.Tap(tap)
+	}
+	if math.Abs(tt.BPM()-100) > 1 {
+		t.Errorf("BPM = %v, want 100", tt.BPM())
+	}
+	runClock(tt.Clock(), nil, 0, 5.5, nil)
+	next := tt.NextBeats(2)
+	if len(next) != 2 || math.Abs(next[0]-5.8) > 0.03 || math.Abs(next[1]-6.4) > 0.03 {
+		t.Errorf("next beats %v, want [5.8 6.4]", next)
+	}
+}
+
+// metronome advances p by one hop at a time until the time end, and
+// returns the times at which it ticked.
+func metronome(p BeatPredictor, advance func(dt float64), end float64) []float64 {
+	var ticks []float64
+	for p.Now() < end {
+		advance(clockHop)
+		if p.Ticked() {
+			ticks = append(ticks, p.Now())
+		}
+	}
+	return ticks
+}
+
+func TestTapTempoMetronome(t *testing.T) {
+	tt := NewTapTempo(testHopSize, testSamplerate)
+	tt.Mode = TapsOnly
+	tt.Lookahead = 0.1
+	if tt.Running() {
+		t.Error("running before any tap")
+	}
+	for _, tap := range []float64{1, 1.6, 2.2, 2.8} {
+		tt.Tap(tap)
+	}
+	if !tt.Running() {
+		t.Fatal("not running after tapping 100 BPM")
+	}
+	ticks := metronome(tt, tt.Advance, 6)
+	if len(ticks) != 10 {
+		t.Fatalf("got ticks %v, want 10", ticks)
+	}
+	for i, tick := range ticks {
+		beat := 0.4 + 0.6*float64(i)
+		if d := tick + tt.Lookahead - beat; d < 0 || d > clockHop {
+			t.Errorf("tick %d at %v, want one hop at most after %v", i, tick, beat-tt.Lookahead)
+		}
+	}
+}
+
+func TestTapTempoTimeout(t *testing.T) {
+	tt := NewTapTempo(testHopSize, testSamplerate)
+	for _, tap := range []float64{0, 0.5, 1, 1.5} {
+		tt.Tap(tap)
+	}
+	// a new tapping after a pause replaces the previous taps
+	for _, tap := range []float64{10, 10.4, 10.8, 11.2} {
+		tt.Tap(tap)
+	}
+	if math.Abs(tt.TapBPM()-150) > 1e-6 {
+		t.Errorf("tap BPM = %v, want 150", tt.TapBPM())
+	}
+}
+
+func TestTapTempoFusion(t *testing.T) {
+	tt := NewTapTempo(testHopSize, testSamplerate)
+	// the detector locks onto double tempo, 140 BPM instead of 70
+	runClock(tt.Clock(), clickTimes(140, 0.1, 10), 60./140, 10, nil)
+	for _, tap := range []float64{7.5, 8.357, 9.214, 10.071} {
+		tt.Tap(tap)
+	}
+	for _, tc := range []struct {
+		mode TapFusion
+		bpm  float64
+	}{{TapsOnly, 70}, {DetectionOnly, 140}, {TapsAsPrior, 70}} {
+		tt.Mode = tc.mode
+		if math.Abs(tt.BPM()-tc.bpm) > 0.5 {
+			t.Errorf("mode %d: BPM = %v, want %v", tc.mode, tt.BPM(), tc.bpm)
+		}
+	}
+	// the prior keeps the phase of the detector
+	tt.Mode = TapsAsPrior
+	next := tt.NextBeats(1)[0]
+	if d := math.Remainder(next-0.1, 60./140); math.Abs(d) > 1e-3 {
+		t.Errorf("next beat %v is off the detected grid by %v", next, d)
+	}
+
+	tt.Reset()
+	if math.Abs(tt.BPM()-140) > 0.5 {
+		t.Errorf("without taps BPM = %v, want the detected 140", tt.BPM())
+	}
+}
@@ -29,7 +29,7 @@ type Tempo struct {
 
 // TempoOrDie constructs a new Tempo object.
 // It panics on any errors.
-func TempoOrDie(mode onsetMode, bufSize, blocksize, samplerate uint) *Tempo {
+func TempoOrDie(mode OnsetMethod, bufSize, blocksize, samplerate uint) *Tempo {
 	if t, err := NewTempo(mode, bufSize, blocksize, samplerate); err == nil {
 		return t
 	} else {
@@ -46,7 +46,10 @@ func TempoOrDie(mode onsetMode, bufSize, blocksize, samplerate uint) *Tempo {
 //     }
 //     defer t.Free()
 func NewTempo(
-	mode onsetMode, bufSize, blockSize, samplerate uint) (*Tempo, error) {
+	mode OnsetMethod, bufSize, blockSize, samplerate uint) (*Tempo, error) {
+	if err := mode.validate(); err != nil {
+		return nil, err
+	}
 	t, err := C.new_aubio_tempo(toCharTPtr(string(mode)),
 		C.uint_t(bufSize), C.uint_t(blockSize), C.uint_t(samplerate))
 	if t == nil {
@@ -112,6 +115,170 @@ func (t *Tempo) GetConfidence() float64 {
 	return float64(C.aubio_tempo_get_confidence(t.o))
 }
 
+// IsBeat returns if a beat was detected in the input Buffer of the
+// latest call to Do.
+func (t *Tempo) IsBeat() bool {
+	if t.o == nil {
+		return false
+	}
+	return C.fvec_get_sample(t.buf.vec, C.uint_t(0)) != 0
+}
+
+// GetSilence returns the tempo detection silence threshold in dB.
+func (t *Tempo) GetSilence() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_silence(t.o))
+}
+
+// GetThreshold returns the tempo detection peak picking threshold.
+func (t *Tempo) GetThreshold() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_threshold(t.o))
+}
+
+// GetLast returns the time of the latest beat detected, in samples.
+// It is 0 while a negative delay moves the beat before the start of
+// the stream.
+func (t *Tempo) GetLast() uint {
+	if t.o == nil || t.lastBeforeStart() {
+		return 0
+	}
+	return uint(C.aubio_tempo_get_last(t.o))
+}
+
+// lastBeforeStart returns if a negative delay moves the latest beat
+// before the start of the stream. aubio adds the delay to the beat as
+// a uint_t, so it wraps around rather than going negative.
+func (t *Tempo) lastBeforeStart() bool {
+	delay := C.aubio_tempo_get_delay(t.o)
+	beat := C.aubio_tempo_get_last(t.o) - delay
+	return int64(beat)+int64(int32(delay)) < 0
+}
+
+// GetLastS returns the time of the latest beat detected, in seconds.
+//     t.Do(buf)
+//     if t.IsBeat() {
+//         fmt.Println("Beat at: ", t.GetLastS())
+//     }
+func (t *Tempo) GetLastS() float64 {
+	if t.o == nil || t.lastBeforeStart() {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_last_s(t.o))
+}
+
+// GetLastMs returns the time of the latest beat detected, in milliseconds.
+func (t *Tempo) GetLastMs() float64 {
+	if t.o == nil || t.lastBeforeStart() {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_last_ms(t.o))
+}
+
+// GetPeriod returns the current beat period in samples.
+func (t *Tempo) GetPeriod() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_period(t.o))
+}
+
+// GetPeriodS returns the current beat period in seconds.
+func (t *Tempo) GetPeriodS() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_period_s(t.o))
+}
+
+// SetDelay sets the delay, in samples, added to the reported beat
+// times. It may be negative to report beats ahead of time, in which
+// case GetLast reports 0 for beats it moves before the start of the
+// stream.
+func (t *Tempo) SetDelay(delay int) {
+	if t.o == nil {
+		return
+	}
+	C.aubio_tempo_set_delay(t.o, C.sint_t(delay))
+}
+
+// SetDelayS sets the delay added to the reported beat times, in seconds.
+func (t *Tempo) SetDelayS(delay float64) {
+	if t.o == nil {
+		return
+	}
+	C.aubio_tempo_set_delay_s(t.o, C.smpl_t(delay))
+}
+
+// SetDelayMs sets the delay added to the reported beat times, in
+// milliseconds.
+func (t *Tempo) SetDelayMs(delay float64) {
+	if t.o == nil {
+		return
+	}
+	C.aubio_tempo_set_delay_ms(t.o, C.smpl_t(delay))
+}
+
+// GetDelay returns the delay added to the reported beat times, in
+// samples. It is negative when beats are reported ahead of time.
+func (t *Tempo) GetDelay() int {
+	if t.o == nil {
+		return 0
+	}
+	// aubio stores the delay signed but returns it as a uint_t
+	return int(int32(C.aubio_tempo_get_delay(t.o)))
+}
+
+// GetDelayS returns the delay added to the reported beat times, in seconds.
+func (t *Tempo) GetDelayS() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_delay_s(t.o))
+}
+
+// GetDelayMs returns the delay added to the reported beat times, in
+// milliseconds.
+func (t *Tempo) GetDelayMs() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_delay_ms(t.o))
+}
+
+// SetTatumSignature sets the number of tatums per beat, between 1 and 64.
+func (t *Tempo) SetTatumSignature(signature uint) error {
+	if t.o == nil {
+		return fmt.Errorf("called SetTatumSignature on empty Tempo")
+	}
+	if C.aubio_tempo_set_tatum_signature(t.o, C.uint_t(signature)) != 0 {
+		return fmt.Errorf("invalid tatum signature %d, expected 1 to 64", signature)
+	}
+	return nil
+}
+
+// WasTatum returns 2 if the latest call to Do found a beat, 1 if it
+// found a tatum between beats, and 0 otherwise. It should be called
+// once after each call to Do.
+func (t *Tempo) WasTatum() uint {
+	if t.o == nil {
+		return 0
+	}
+	return uint(C.aubio_tempo_was_tatum(t.o))
+}
+
+// GetLastTatum returns the time of the latest tatum, in samples.
+func (t *Tempo) GetLastTatum() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_tempo_get_last_tatum(t.o))
+}
+
 // Free frees the aubio_temp_t object's memory.
 func (t *Tempo) Free() {
 	if t.o == nil {
@@ -132,6 +299,10 @@ type BeatTracker struct {
 }
 
 // NewBeatTracker constructs a new BeatTracker object.
+// bufSize is the length of the onset detection function window passed
+// to Do, in hops of blockSize samples. aubio_tempo uses the next power
+// of two of 5.8 seconds worth of hops, and calls Do every bufSize/4
+// hops. See NoveltyBeatTracker, which manages the window itself.
 // It is the Callers responsibility to call Free on the returned
 // BeatTracker object or leak memory.
 //     t, err := NewBeatTracker(bufSize, blockSize, samplerate)
@@ -144,15 +315,20 @@ func NewBeatTracker(bufSize, blockSize, samplerate uint) (*BeatTracker, error) {
 	if t == nil {
 		return nil, fmt.Errorf("failure creating BeatTracker object %q", err)
 	}
-	return &BeatTracker{t, NewSimpleBuffer(bufSize)}, nil
+	// at most one beat per hop of the bufSize/4 step, plus the count
+	return &BeatTracker{t, NewSimpleBuffer(bufSize/4 + 1)}, nil
 }
 
-// Get the detected beat locations
+// Get the detected beat locations.
+// The first sample holds the number of beats plus one, and the
+// following ones the beat positions, in hops from the start of the
+// last bufSize/4 hops of the window.
 func (t *BeatTracker) Buffer() *SimpleBuffer {
 	return t.buf
 }
 
-// Do executes the beattracking detection on an input Buffer.
+// Do executes the beattracking detection on a window of bufSize onset
+// detection function values, one per hop, the oldest first.
 // The beat locations are stored in the BeatTracker struct's buf
 func (t *BeatTracker) Do(input *SimpleBuffer) {
 	if t.o == nil {
@@ -189,6 +365,22 @@ func (t *BeatTracker) GetConfidence() float64 {
 	return float64(C.aubio_beattracking_get_confidence(t.o))
 }
 
+// GetPeriod returns the current beat period in samples.
+func (t *BeatTracker) GetPeriod() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_beattracking_get_period(t.o))
+}
+
+// GetPeriodS returns the current beat period in seconds.
+func (t *BeatTracker) GetPeriodS() float64 {
+	if t.o == nil {
+		return 0
+	}
+	return float64(C.aubio_beattracking_get_period_s(t.o))
+}
+
 // Free frees the aubio_temp_t object's memory.
 func (t *BeatTracker) Free() {
 	if t.o != nil {
@@ -0,0 +1,289 @@
+package aubio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// TempoSegmentKind describes how the tempo behaves over a TempoSegment.
+type TempoSegmentKind string
+
+const (
+	// TempoConstant segments keep the same tempo throughout.
+	TempoConstant TempoSegmentKind = "constant"
+	// TempoRamp segments speed up or slow down gradually.
+	TempoRamp TempoSegmentKind = "ramp"
+	// TempoJump segments have no duration, and mark an abrupt change
+	// of tempo between the segments either side.
+	TempoJump TempoSegmentKind = "jump"
+)
+
+const (
+	// tempoMapSmoothing is the size of the median filter applied to
+	// the tempo of each beat.
+	tempoMapSmoothing = 5
+	// tempoMapTolerance is how far in BPM the smoothed tempo may be
+	// from the line fitted over a segment.
+	tempoMapTolerance = 1.5
+	// tempoMapJump is the change in BPM from one beat to the next
+	// above which a tempo change is a jump rather than a ramp.
+	tempoMapJump = 4
+	// tempoMapMinBeats is the smallest number of beats in a segment.
+	tempoMapMinBeats = 4
+)
+
+// TempoPoint is the tempo at a beat.
+type TempoPoint struct {
+	Time float64 `json:"time"`
+	BPM  float64 `json:"bpm"`
+}
+
+// TempoSegment is a section of constant or gradually changing tempo.
+type TempoSegment struct {
+	Kind     TempoSegmentKind `json:"kind"`
+	Start    float64          `json:"start"`
+	End      float64          `json:"end"`
+	StartBPM float64          `json:"start_bpm"`
+	EndBPM   float64          `json:"end_bpm"`
+}
+
+// TempoMap is the tempo of a whole recording.
+type TempoMap struct {
+	// Beats are the detected beat times in seconds.
+	Beats []float64 `json:"beats"`
+	// BPM is the smoothed tempo at each beat but the first.
+	BPM []TempoPoint `json:"bpm"`
+	// Segments cover the beats in order.
+	Segments []TempoSegment `json:"segments"`
+}
+
+// AnalyzeTempoMap runs Tempo over the whole of src, using a window
+// twice its block size, and builds the TempoMap of the beats found.
+func AnalyzeTempoMap(src *Source) (TempoMap, error) {
+	hop := src.BlockSize()
+	t, err := NewTempo(OnsetDefault, 2*hop, hop, src.Samplerate())
+	if err != nil {
+		return TempoMap{}, err
+	}
+	defer t.Free()
+	buf := NewSimpleBuffer(hop)
+	defer buf.Free()
+	var beats []float64
+	for {
+		n := src.Do(buf)
+		if n == 0 {
+			break
+		}
+		t.Do(buf)
+		if t.IsBeat() {
+			beats = append(beats, t.GetLastS())
+		}
+		if n < hop {
+			break
+		}
+	}
+	if len(beats) < 2 {
+		return TempoMap{Beats: beats}, errors.New("not enough beats found to build a tempo map")
+	}
+	return BuildTempoMap(beats), nil
+}
+
+// BuildTempoMap smooths the tempo between beat times given in
+// seconds and segments it. Repeated beat times are kept once.
+func BuildTempoMap(beats []float64) TempoMap {
+	sorted := append([]float64(nil), beats...)
+	sort.Float64s(sorted)
+	var m TempoMap
+	for i, b := range sorted {
+		if i == 0 || b != sorted[i-1] {
+			m.Beats = append(m.Beats, b)
+		}
+	}
+	if len(m.Beats) < 2 {
+		return m
+	}
+	raw := make([]float64, 0, len(m.Beats)-1)
+	for i := 1; i < len(m.Beats); i++ {
+		raw = append(raw, 60/(m.Beats[i]-m.Beats[i-1]))
+	}
+	for i := range raw {
+		lo := maxInt(0, i-tempoMapSmoothing/2)
+		hi := minInt(len(raw), i+tempoMapSmoothing/2+1)
+		window := append([]float64(nil), raw[lo:hi]...)
+		sort.Float64s(window)
+		m.BPM = append(m.BPM, TempoPoint{Time: m.Beats[i+1], BPM: window[len(window)/2]})
+	}
+	m.Segments = segmentTempo(m.BPM)
+	return m
+}
+
+// segmentTempo greedily fits lines over the tempo curve, starting a
+// new segment whenever a point is too far from the line, then moves
+// each boundary that is not a jump to where the two lines fit best.
+func segmentTempo(points []TempoPoint) []TempoSegment {
+	bounds := []int{0}
+	for start := 0; start < len(points); {
+		end := minInt(len(points), start+tempoMapMinBeats)
+		for end < len(points) && fitsLine(points[start:end+1]) {
+			end++
+		}
+		bounds = append(bounds, end)
+		start = end
+	}
+	for i := 1; i < len(bounds)-1; i++ {
+		if math.Abs(points[bounds[i]].BPM-points[bounds[i]-1].BPM) > tempoMapJump {
+			continue
+		}
+		best := math.Inf(1)
+		for b := bounds[i-1] + tempoMapMinBeats; b <= bounds[i+1]-tempoMapMinBeats; b++ {
+			if e := lineError(points[bounds[i-1]:b]) + lineError(points[b:bounds[i+1]]); e < best {
+				best = e
+				bounds[i] = b
+			}
+		}
+	}
+
+	var segments []TempoSegment
+	for i := 1; i < len(bounds); i++ {
+		seg := points[bounds[i-1]:bounds[i]]
+		slope, intercept := fitLine(seg)
+		s := TempoSegment{
+			Kind:     TempoConstant,
+			Start:    seg[0].Time,
+			End:      seg[len(seg)-1].Time,
+			StartBPM: slope*seg[0].Time + intercept,
+			EndBPM:   slope*seg[len(seg)-1].Time + intercept,
+		}
+		if math.Abs(s.EndBPM-s.StartBPM) > tempoMapTolerance {
+			s.Kind = TempoRamp
+		} else {
+			mean := (s.StartBPM + s.EndBPM) / 2
+			s.StartBPM, s.EndBPM = mean, mean
+		}
+		if n := len(segments); n > 0 && math.Abs(s.StartBPM-segments[n-1].EndBPM) > tempoMapJump {
+			segments = append(segments, TempoSegment{
+				Kind:     TempoJump,
+				Start:    s.Start,
+				End:      s.Start,
+				StartBPM: segments[n-1].EndBPM,
+				EndBPM:   s.StartBPM,
+			})
+		}
+		segments = append(segments, s)
+	}
+	return segments
+}
+
+// lineError returns the squared error of the line fitted over points.
+func lineError(points []TempoPoint) float64 {
+	slope, intercept := fitLine(points)
+	var e float64
+	for _, p := range points {
+		d := slope*p.Time + intercept - p.BPM
+		e += d * d
+	}
+	return e
+}
+
+func fitsLine(points []TempoPoint) bool {
+	slope, intercept := fitLine(points)
+	for _, p := range points {
+		if math.Abs(slope*p.Time+intercept-p.BPM) > tempoMapTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// fitLine returns the least squares line through points.
+func fitLine(points []TempoPoint) (slope, intercept float64) {
+	var st, sb float64
+	for _, p := range points {
+		st += p.Time
+		sb += p.BPM
+	}
+	n := float64(len(points))
+	mt, mb := st/n, sb/n
+	var cov, variance float64
+	for _, p := range points {
+		cov += (p.Time - mt) * (p.BPM - mb)
+		variance += (p.Time - mt) * (p.Time - mt)
+	}
+	if variance == 0 {
+		return 0, mb
+	}
+	slope = cov / variance
+	return slope, mb - slope*mt
+}
+
+// BPMAt returns the smoothed tempo at t seconds, or 0 when unknown.
+func (m TempoMap) BPMAt(t float64) float64 {
+	if len(m.BPM) == 0 {
+		return 0
+	}
+	i := sort.Search(len(m.BPM), func(i int) bool { return m.BPM[i].Time >= t })
+	return m.BPM[minInt(i, len(m.BPM)-1)].BPM
+}
+
+// WriteJSON writes the TempoMap as JSON.
+func (m TempoMap) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// WriteCSV writes one row per beat with its time, smoothed tempo and
+// the kind of segment it belongs to.
+func (m TempoMap) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "bpm", "segment", "kind"})
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', 6, 64) }
+	seg := 0
+	for i, beat := range m.Beats {
+		row := []string{format(beat), "", "", ""}
+		if i > 0 {
+			row[1] = format(m.BPM[i-1].BPM)
+		}
+		for seg < len(m.Segments)-1 && (m.Segments[seg].Kind == TempoJump || beat > m.Segments[seg].End) {
+			seg++
+		}
+		if len(m.Segments) > 0 && i > 0 {
+			row[2] = strconv.Itoa(seg)
+			row[3] = string(m.Segments[seg].Kind)
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSegmentsCSV writes one row per segment.
+func (m TempoMap) WriteSegmentsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"kind", "start", "end", "start_bpm", "end_bpm"})
+	format := func(f float64) string { return strconv.FormatFloat(f, 'f', 6, 64) }
+	for _, s := range m.Segments {
+		cw.Write([]string{string(s.Kind), format(s.Start), format(s.End), format(s.StartBPM), format(s.EndBPM)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
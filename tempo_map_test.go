@@ -0,0 +1,120 @@
+package aubio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// beatsAt returns n beat times starting at t, whose tempo goes
+// linearly from bpm to endBPM.
+func beatsAt(t, bpm, endBPM float64, n int) []float64 {
+	beats := make([]float64, n)
+	for i := range beats {
+		beats[i] = t
+		t += 60 / (bpm + (endBPM-bpm)*float64(i)/float64(n))
+	}
+	return beats
+}
+
+func TestBuildTempoMap(t *testing.T) {
+	var beats []float64
+	beats = append(beats, beatsAt(0, 120, 120, 32)...)
+	beats = append(beats, beatsAt(beats[len(beats)-1]+0.5, 128, 128, 32)...)
+	beats = append(beats, beatsAt(beats[len(beats)-1]+60./128, 128, 140, 48)...)
+	beats = append(beats, beatsAt(beats[len(beats)-1]+60./140, 140, 140, 32)...)
+	m := BuildTempoMap(beats)
+
+	var kinds []TempoSegmentKind
+	for _, s := range m.Segments {
+		kinds = append(kinds, s.Kind)
+	}
+	want := []TempoSegmentKind{TempoConstant, TempoJump, TempoConstant, TempoRamp, TempoConstant}
+	if len(kinds) != len(want) {
+		t.Fatalf("segments %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("segments %v, want %v", kinds, want)
+		}
+	}
+	for i, bpm := range []float64{120, 0, 128, 0, 140} {
+		if s := m.Segments[i]; bpm > 0 && math.Abs(s.StartBPM-bpm) > 0.5 {
+			t.Errorf("segment %d %+v, want %v BPM", i, s, bpm)
+		}
+	}
+	if ramp := m.Segments[3]; math.Abs(ramp.StartBPM-128) > 1.5 || math.Abs(ramp.EndBPM-140) > 1.5 {
+		t.Errorf("ramp %+v, want 128 to 140 BPM", ramp)
+	}
+	if got := m.BPMAt(5); math.Abs(got-120) > 1e-6 {
+		t.Errorf("BPMAt(5) = %v, want 120", got)
+	}
+}
+
+func TestTempoMapMedianRejectsMissedBeat(t *testing.T) {
+	beats := beatsAt(0, 100, 100, 20)
+	// a missed beat doubles one interval
+	beats = append(beats[:10], beats[11:]...)
+	m := BuildTempoMap(beats)
+	if len(m.Segments) != 1 || m.Segments[0].Kind != TempoConstant {
+		t.Errorf("segments %+v, want a single constant segment", m.Segments)
+	}
+}
+
+func TestTempoMapRepeatedBeat(t *testing.T) {
+	beats := beatsAt(0, 100, 100, 20)
+	beats = append(beats, beats[5])
+	m := BuildTempoMap(beats)
+	if len(m.Beats) != 20 {
+		t.Errorf("got %d beats, want the repeated one dropped", len(m.Beats))
+	}
+	if len(m.Segments) != 1 || m.Segments[0].Kind != TempoConstant ||
+		math.Abs(m.Segments[0].StartBPM-100) > 0.01 {
+		t.Errorf("segments %+v, want a single constant segment at 100 BPM", m.Segments)
+	}
+	if err := m.WriteJSON(&bytes.Buffer{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTempoMapExport(t *testing.T) {
+	m := BuildTempoMap(append(beatsAt(0, 120, 120, 10), beatsAt(5, 90, 90, 10)...))
+
+	var js bytes.Buffer
+	if err := m.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded TempoMap
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Beats) != len(m.Beats) || len(decoded.Segments) != len(m.Segments) ||
+		decoded.Segments[1].Kind != TempoJump {
+		t.Errorf("JSON round trip got %+v", decoded)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(m.Beats)+1 || rows[0][0] != "time" {
+		t.Fatalf("CSV has %d rows, want %d", len(rows), len(m.Beats)+1)
+	}
+	if last := rows[len(rows)-1]; last[2] != "2" || last[3] != string(TempoConstant) {
+		t.Errorf("last CSV row %v, want segment 2", last)
+	}
+
+	buf.Reset()
+	if err := m.WriteSegmentsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := csv.NewReader(&buf).ReadAll(); len(rows) != len(m.Segments)+1 {
+		t.Errorf("segments CSV has %d rows, want %d", len(rows), len(m.Segments)+1)
+	}
+}
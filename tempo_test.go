@@ -0,0 +1,79 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestTempo(t *testing.T) *Tempo {
+	tempo, err := NewTempo(OnsetDefault, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tempo
+}
+
+func TestTempoDelay(t *testing.T) {
+	tempo := newTestTempo(t)
+	defer tempo.Free()
+	for _, delay := range []int{512, 0, -256} {
+		tempo.SetDelay(delay)
+		if got := tempo.GetDelay(); got != delay {
+			t.Errorf("SetDelay(%d): GetDelay = %d", delay, got)
+		}
+	}
+	tempo.SetDelayS(-0.5)
+	if got := tempo.GetDelay(); got != -testSamplerate/2 {
+		t.Errorf("SetDelayS(-0.5): GetDelay = %d, want %d", got, -testSamplerate/2)
+	}
+	if got := tempo.GetDelayS(); math.Abs(got+0.5) > 1e-6 {
+		t.Errorf("SetDelayS(-0.5): GetDelayS = %v", got)
+	}
+	tempo.SetDelayMs(100)
+	if got := tempo.GetDelayMs(); math.Abs(got-100) > 1e-3 {
+		t.Errorf("SetDelayMs(100): GetDelayMs = %v", got)
+	}
+}
+
+func TestTempoNegativeDelayBeforeStart(t *testing.T) {
+	tempo := newTestTempo(t)
+	defer tempo.Free()
+	// no beat yet, so the latest one is at 0 and the delay moves it
+	// before the start, where aubio would wrap around
+	tempo.SetDelay(-testSamplerate)
+	if last, s, ms := tempo.GetLast(), tempo.GetLastS(), tempo.GetLastMs(); last != 0 || s != 0 || ms != 0 {
+		t.Errorf("got %d, %v and %v before the start, want 0", last, s, ms)
+	}
+	tempo.SetDelay(testHopSize)
+	if got := tempo.GetLast(); got != testHopSize {
+		t.Errorf("GetLast = %d with a positive delay, want %d", got, testHopSize)
+	}
+}
+
+func TestTempoThresholdAndSilence(t *testing.T) {
+	tempo := newTestTempo(t)
+	defer tempo.Free()
+	tempo.SetThreshold(0.5)
+	if got := tempo.GetThreshold(); math.Abs(got-0.5) > 1e-6 {
+		t.Errorf("GetThreshold = %v, want 0.5", got)
+	}
+	tempo.SetSilence(-60)
+	if got := tempo.GetSilence(); math.Abs(got+60) > 1e-6 {
+		t.Errorf("GetSilence = %v, want -60", got)
+	}
+	if err := tempo.SetTatumSignature(0); err == nil {
+		t.Error("expected an error for a tatum signature of 0")
+	}
+	if err := tempo.SetTatumSignature(3); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTempoFree(t *testing.T) {
+	tempo := newTestTempo(t)
+	tempo.Free()
+	// must not crash once freed
+	if tempo.IsBeat() || tempo.GetDelay() != 0 || tempo.GetBpm() != 0 {
+		t.Error("expected zero values once freed")
+	}
+}
@@ -0,0 +1,208 @@
+package aubio
+
+import (
+	"log"
+	"math"
+	"sort"
+)
+
+const (
+	// tuningMaxPeaks is the number of spectral peaks pooled per frame.
+	tuningMaxPeaks = 8
+	// tuningPeakFloor is the magnitude, relative to the strongest bin,
+	// below which spectral peaks are ignored.
+	tuningPeakFloor = 0.1
+	// tuningMinHz is the lowest frequency pooled, below which the
+	// resolution of the spectrum is too coarse.
+	tuningMinHz = 80
+)
+
+// Tuning is an estimated tuning reference.
+type Tuning struct {
+	// Cents is the deviation from the nominal A4, between -50 and 50.
+	Cents float64
+	// Reference is the estimated frequency of A4 in Hz.
+	Reference float64
+	// Confidence is between 0, when the pooled estimates are spread
+	// evenly around the semitone, and 1 when they all agree.
+	Confidence float64
+}
+
+// TuningEstimator pools pitch estimates across a recording to find how
+// far its tuning is from A440. The result can be passed on to
+// Chroma.SetTuning or used to correct MIDI note numbers.
+//
+// Estimates are pooled as a weighted circular mean of their deviation
+// from the nearest equal tempered semitone, so a recording a quarter
+// tone off still gives a stable result.
+type TuningEstimator struct {
+	pitch      *Pitch
+	norm       *SimpleBuffer
+	bufSize    uint
+	samplerate uint
+	sumCos     float64
+	sumSin     float64
+	weight     float64
+	confident  bool
+
+	// A4 is the nominal reference in Hz. Defaults to 440.
+	A4 float64
+	// MinConfidence is the Pitch confidence below which estimates
+	// are ignored by Do. Defaults to 0.8. It is not used with schmitt,
+	// fcomb and mcomb, which report no confidence: their estimates
+	// are all pooled with the same weight.
+	MinConfidence float64
+}
+
+// NewTuningEstimator constructs a new TuningEstimator on top of a new
+// Pitch object reporting in Hz.
+// It is the Callers responsibility to call Free on the returned
+// TuningEstimator object or leak memory.
+//     te, err := NewTuningEstimator(PitchYinfft, bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer te.Free()
+func NewTuningEstimator(method PitchMethod, bufSize, hopSize, samplerate uint) (*TuningEstimator, error) {
+	p, err := NewPitch(method, bufSize, hopSize, samplerate)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetUnit(PitchOutFreq); err != nil {
+		p.Free()
+		return nil, err
+	}
+	return &TuningEstimator{
+		pitch:         p,
+		norm:          NewSimpleBuffer(bufSize/2 + 1),
+		bufSize:       bufSize,
+		samplerate:    samplerate,
+		confident:     method.reportsConfidence(),
+		A4:            440,
+		MinConfidence: 0.8,
+	}, nil
+}
+
+// Pitch returns the underlying Pitch object.
+func (te *TuningEstimator) Pitch() *Pitch {
+	return te.pitch
+}
+
+// Add pools a single frequency estimate with the given weight.
+func (te *TuningEstimator) Add(hz, weight float64) {
+	if hz <= 0 || weight <= 0 {
+		return
+	}
+	angle := 2 * math.Pi * 12 * math.Log2(hz/te.A4)
+	te.sumCos += weight * math.Cos(angle)
+	te.sumSin += weight * math.Sin(angle)
+	te.weight += weight
+}
+
+// Do runs the pitch detection on a hopSize input Buffer and pools the
+// estimate when Pitch is confident enough.
+func (te *TuningEstimator) Do(input *SimpleBuffer) {
+	if te.pitch == nil {
+		log.Println("Called Do on empty TuningEstimator. Maybe you called Free previously?")
+		return
+	}
+	te.pitch.Do(input)
+	if !te.confident {
+		te.Add(te.pitch.Buffer().Get(0), 1)
+	} else if conf := te.pitch.GetConfidence(); conf >= te.MinConfidence {
+		te.Add(te.pitch.Buffer().Get(0), conf)
+	}
+}
+
+// DoSpectrum pools the strongest peaks of a bufSize spectrum from
+// PhaseVoc, weighted by their magnitude. Their frequencies are refined
+// with QuadraticPeakPos. It suits polyphonic material better than Do.
+func (te *TuningEstimator) DoSpectrum(in *ComplexBuffer) {
+	if te.norm == nil {
+		log.Println("Called DoSpectrum on empty TuningEstimator. Maybe you called Free previously?")
+		return
+	}
+	if in.Size() != te.norm.Size() {
+		log.Printf("Called DoSpectrum with a spectrum of %d bins, want %d", in.Size(), te.norm.Size())
+		return
+	}
+	mag := in.Norm()
+	te.norm.SetData(mag)
+	var max float64
+	for _, m := range mag {
+		max = math.Max(max, m)
+	}
+	if max == 0 {
+		return
+	}
+	binHz := float64(te.samplerate) / float64(te.bufSize)
+	var peaks []int
+	for k := 1; k+1 < len(mag); k++ {
+		if float64(k)*binHz >= tuningMinHz && mag[k] > mag[k-1] && mag[k] >= mag[k+1] &&
+			mag[k] >= tuningPeakFloor*max {
+			peaks = append(peaks, k)
+		}
+	}
+	sort.Slice(peaks, func(i, j int) bool { return mag[peaks[i]] > mag[peaks[j]] })
+	if len(peaks) > tuningMaxPeaks {
+		peaks = peaks[:tuningMaxPeaks]
+	}
+	for _, k := range peaks {
+		pos := te.norm.QuadraticPeakPos(uint(k))
+		te.Add(pos*binHz, te.norm.QuadraticPeakMag(pos))
+	}
+}
+
+// Estimate returns the tuning of the estimates pooled so far.
+func (te *TuningEstimator) Estimate() Tuning {
+	if te.weight == 0 {
+		return Tuning{Reference: te.A4}
+	}
+	cents := math.Atan2(te.sumSin, te.sumCos) / (2 * math.Pi) * 100
+	return Tuning{
+		Cents:      cents,
+		Reference:  te.A4 * math.Pow(2, cents/1200),
+		Confidence: math.Hypot(te.sumCos, te.sumSin) / te.weight,
+	}
+}
+
+// Reset forgets the pooled estimates.
+func (te *TuningEstimator) Reset() {
+	te.sumCos, te.sumSin, te.weight = 0, 0, 0
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (te *TuningEstimator) Free() {
+	if te.pitch != nil {
+		te.pitch.Free()
+		te.pitch = nil
+	}
+	if te.norm != nil {
+		te.norm.Free()
+		te.norm = nil
+	}
+}
+
+// EstimateTuning reads the whole of src and estimates its tuning from
+// the pitch detected by method, using a window four times the block
+// size of src.
+func EstimateTuning(src *Source, method PitchMethod) (Tuning, error) {
+	te, err := NewTuningEstimator(method, 4*src.BlockSize(), src.BlockSize(), src.Samplerate())
+	if err != nil {
+		return Tuning{}, err
+	}
+	defer te.Free()
+	buf := NewSimpleBuffer(src.BlockSize())
+	defer buf.Free()
+	for {
+		n := src.Do(buf)
+		if n == 0 {
+			break
+		}
+		te.Do(buf)
+		if n < src.BlockSize() {
+			break
+		}
+	}
+	return te.Estimate(), nil
+}
@@ -0,0 +1,103 @@
+package aubio
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestTuningEstimator(t *testing.T) *TuningEstimator {
+	te, err := NewTuningEstimator(PitchYinfft, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return te
+}
+
+func TestTuningPooling(t *testing.T) {
+	te := newTestTuningEstimator(t)
+	defer te.Free()
+	// frequencies are computed in double precision, as MidiToFreq is
+	// single precision
+	for _, midi := range []float64{45, 52, 57, 61, 64, 69, 76} {
+		te.Add(440*math.Pow(2, (midi+0.2-69)/12), 1)
+	}
+	got := te.Estimate()
+	if math.Abs(got.Cents-20) > 1e-6 || math.Abs(got.Confidence-1) > 1e-6 {
+		t.Errorf("got %+v, want +20 cents", got)
+	}
+	if want := 440 * math.Pow(2, 20./1200); math.Abs(got.Reference-want) > 1e-6 {
+		t.Errorf("reference %v, want %v", got.Reference, want)
+	}
+
+	// estimates either side of a quarter tone wrap around the semitone
+	te.Reset()
+	te.Add(440*math.Pow(2, 0.45/12), 1)
+	te.Add(440*math.Pow(2, 2.55/12), 1)
+	if got := te.Estimate(); math.Abs(math.Abs(got.Cents)-50) > 1e-6 {
+		t.Errorf("got %+v, want a quarter tone", got)
+	}
+
+	te.Reset()
+	if got := te.Estimate(); got.Cents != 0 || got.Reference != 440 {
+		t.Errorf("empty estimator got %+v", got)
+	}
+}
+
+func TestTuningEstimator(t *testing.T) {
+	te := newTestTuningEstimator(t)
+	defer te.Free()
+	buf := NewSimpleBuffer(testHopSize)
+	defer buf.Free()
+	// an A major arpeggio tuned to A4 = 445Hz
+	for _, midi := range []float64{57, 61, 64, 69} {
+		signal := tone(445*math.Pow(2, (midi-69)/12), testSamplerate, testSamplerate/2)
+		for i := 0; i+testHopSize <= len(signal); i += testHopSize {
+			buf.SetData(signal[i : i+testHopSize])
+			te.Do(buf)
+		}
+	}
+	want := 1200 * math.Log2(445./440)
+	if got := te.Estimate(); math.Abs(got.Cents-want) > 3 {
+		t.Errorf("got %+v, want %.1f cents", got, want)
+	}
+}
+
+func TestTuningDoSpectrum(t *testing.T) {
+	te := newTestTuningEstimator(t)
+	defer te.Free()
+	// octaves of A4 = 445Hz drawn as parabolas, which QuadraticPeakPos
+	// locates exactly between bins
+	const width = 3
+	binHz := float64(testSamplerate) / testBufSize
+	norm := make([]float64, testBufSize/2+1)
+	for _, midi := range []float64{57, 69, 81} {
+		center := 445 * math.Pow(2, (midi-69)/12) / binHz
+		for k := int(center) - width; k <= int(center)+width+1; k++ {
+			d := (float64(k) - center) / width
+			norm[k] += math.Max(0, 1-d*d)
+		}
+	}
+	grain := NewComplexBuffer(testBufSize)
+	defer grain.Free()
+	grain.SetNorm(norm)
+	te.DoSpectrum(grain)
+	want := 1200 * math.Log2(445./440)
+	if got := te.Estimate(); math.Abs(got.Cents-want) > 0.5 || got.Confidence < 0.99 {
+		t.Errorf("got %+v, want %.1f cents", got, want)
+	}
+}
+
+func TestTuningDoSpectrumSize(t *testing.T) {
+	te, err := NewTuningEstimator(PitchYinfft, testBufSize, testHopSize, testSamplerate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer te.Free()
+	// a spectrum larger than the estimator's is ignored
+	grain := triangleSpectrum(2*testBufSize, 100, 2)
+	defer grain.Free()
+	te.DoSpectrum(grain)
+	if got := te.Estimate(); got.Confidence != 0 {
+		t.Errorf("got %+v from a mismatched spectrum, want nothing pooled", got)
+	}
+}
@@ -0,0 +1,106 @@
+package aubio
+
+/*
+#cgo LDFLAGS: -laubio
+#define AUBIO_UNSTABLE 1
+#include <aubio/aubio.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"log"
+)
+
+// SpectralWhitening is a wrapper for the aubio_spectral_whitening_t
+// object. It divides each bin of a spectrum by a slowly decaying
+// memory of its peak, which evens out the spectrum across loud and
+// quiet passages. Use it between PhaseVoc.Do and the objects consuming
+// its grain, such as SpecDesc, FilterBank or MFCC.
+type SpectralWhitening struct {
+	o *C.aubio_spectral_whitening_t
+}
+
+// NewSpectralWhitening constructs a new SpectralWhitening object for
+// the spectra of a PhaseVoc of bufSize and hopSize.
+// It is the Callers responsibility to call Free on the returned
+// SpectralWhitening object or leak memory.
+//     sw, err := NewSpectralWhitening(bufSize, hopSize, samplerate)
+//     if err != nil {
+//         // handle error
+//     }
+//     defer sw.Free()
+func NewSpectralWhitening(bufSize, hopSize, samplerate uint) (*SpectralWhitening, error) {
+	sw, err := C.new_aubio_spectral_whitening(C.uint_t(bufSize), C.uint_t(hopSize), C.uint_t(samplerate))
+	if sw == nil {
+		return nil, fmt.Errorf("failure creating SpectralWhitening object %q", err)
+	}
+	return &SpectralWhitening{o: sw}, nil
+}
+
+// Do whitens the norm of a spectrum in place.
+func (sw *SpectralWhitening) Do(grain *ComplexBuffer) {
+	if sw.o == nil {
+		log.Println("Called Do on empty SpectralWhitening. Maybe you called Free previously?")
+		return
+	}
+	C.aubio_spectral_whitening_do(sw.o, grain.data)
+}
+
+// Reset forgets the peak memory.
+func (sw *SpectralWhitening) Reset() {
+	if sw.o == nil {
+		return
+	}
+	C.aubio_spectral_whitening_reset(sw.o)
+}
+
+// SetRelaxTime sets the time in seconds the peak memory takes to decay
+// by 60dB. It must be positive. Defaults to 250s.
+func (sw *SpectralWhitening) SetRelaxTime(relaxTime float64) error {
+	if sw.o == nil {
+		return fmt.Errorf("called SetRelaxTime on empty SpectralWhitening")
+	}
+	if relaxTime <= 0 {
+		return fmt.Errorf("invalid relax time %v, must be positive", relaxTime)
+	}
+	C.aubio_spectral_whitening_set_relax_time(sw.o, C.smpl_t(relaxTime))
+	return nil
+}
+
+// GetRelaxTime returns the relax time in seconds.
+func (sw *SpectralWhitening) GetRelaxTime() float64 {
+	if sw.o == nil {
+		return 0
+	}
+	return float64(C.aubio_spectral_whitening_get_relax_time(sw.o))
+}
+
+// SetFloor sets the smallest peak memory, below which quiet bins are
+// not amplified any further. It must be positive. Defaults to 1e-4.
+func (sw *SpectralWhitening) SetFloor(floor float64) error {
+	if sw.o == nil {
+		return fmt.Errorf("called SetFloor on empty SpectralWhitening")
+	}
+	if floor <= 0 {
+		return fmt.Errorf("invalid floor %v, must be positive", floor)
+	}
+	C.aubio_spectral_whitening_set_floor(sw.o, C.smpl_t(floor))
+	return nil
+}
+
+// GetFloor returns the smallest peak memory.
+func (sw *SpectralWhitening) GetFloor() float64 {
+	if sw.o == nil {
+		return 0
+	}
+	return float64(C.aubio_spectral_whitening_get_floor(sw.o))
+}
+
+// Free frees the memory allocated by the aubio library for this object.
+func (sw *SpectralWhitening) Free() {
+	if sw.o != nil {
+		C.del_aubio_spectral_whitening(sw.o)
+		sw.o = nil
+	}
+}